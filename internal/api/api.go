@@ -0,0 +1,77 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/costa-app/costa-cli/internal/auth"
+	"github.com/costa-app/costa-cli/internal/debug"
+	"github.com/costa-app/costa-cli/internal/httpclient"
+)
+
+// DefaultTimeout is the request timeout for Costa API calls
+const DefaultTimeout = 5 * time.Second
+
+// getJSON performs an authenticated GET against the Costa API and decodes the JSON response
+func getJSON(ctx context.Context, path string, query url.Values, out any) error {
+	// Ensure OAuth token is valid
+	oauthToken, err := auth.EnsureOAuthTokenValid(ctx)
+	if err != nil {
+		debug.Printf("api: Failed to get valid OAuth token: %v\n", err)
+		return err
+	}
+
+	reqURL := auth.GetBaseURL() + path
+	if len(query) > 0 {
+		reqURL += "?" + query.Encode()
+	}
+	debug.Printf("api: Making request to %s\n", reqURL)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", oauthToken.AccessToken))
+	req.Header.Set("Accept", "application/json")
+	auth.SetOrgHeader(req)
+
+	client, err := httpclient.New(DefaultTimeout)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		debug.Printf("api: HTTP request failed: %v\n", err)
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	debug.Printf("api: Received HTTP %d from %s\n", resp.StatusCode, path)
+
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		return fmt.Errorf("request to %s failed: HTTP %d: %w", path, resp.StatusCode, auth.ErrLoginRequired)
+	}
+
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		debug.Printf("api: Error response body: %s\n", string(bodyBytes))
+		return fmt.Errorf("request to %s failed: HTTP %d", path, resp.StatusCode)
+	}
+
+	debug.Printf("api: Response body: %s\n", string(bodyBytes))
+
+	if err := json.Unmarshal(bodyBytes, out); err != nil {
+		// Not wrapped: a malformed API response is not a user config error
+		return fmt.Errorf("failed to decode response from %s: %v", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,21 @@
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/costa-app/costa-cli/internal/auth"
+)
+
+// GetIdentity fetches the logged-in account from /api/v1/me and caches it
+func GetIdentity(ctx context.Context) (*auth.Identity, error) {
+	var identity auth.Identity
+	if err := getJSON(ctx, "/api/v1/me", nil, &identity); err != nil {
+		return nil, err
+	}
+	identity.FetchedAt = time.Now().UTC().Truncate(time.Second)
+
+	// A failed cache write only means the status line cannot show the identity yet
+	_ = auth.SaveIdentity(&identity)
+	return &identity, nil
+}
@@ -0,0 +1,21 @@
+package api
+
+import (
+	"context"
+
+	"github.com/costa-app/costa-cli/internal/auth"
+)
+
+// organizationsResponse represents the API response from /api/v1/organizations
+type organizationsResponse struct {
+	Organizations []auth.Organization `json:"organizations"`
+}
+
+// ListOrganizations fetches the organizations the logged-in account belongs to
+func ListOrganizations(ctx context.Context) ([]auth.Organization, error) {
+	var resp organizationsResponse
+	if err := getJSON(ctx, "/api/v1/organizations", nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Organizations, nil
+}
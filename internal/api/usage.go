@@ -0,0 +1,131 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Grouping keys accepted by the usage history endpoint
+const (
+	GroupByDay         = "day"
+	GroupByModel       = "model"
+	GroupByIntegration = "integration"
+	GroupByProject     = "project"
+)
+
+// FlexibleFloat handles JSON fields that can be either a number or a string like "-"
+type FlexibleFloat struct {
+	Value   float64
+	IsValid bool
+}
+
+// UnmarshalJSON implements custom unmarshaling for FlexibleFloat
+func (f *FlexibleFloat) UnmarshalJSON(data []byte) error {
+	// Try to unmarshal as float64 first
+	var num float64
+	if err := json.Unmarshal(data, &num); err == nil {
+		f.Value = num
+		f.IsValid = true
+		return nil
+	}
+
+	// Try to unmarshal as string
+	var str string
+	if err := json.Unmarshal(data, &str); err == nil {
+		// If it's a dash or empty, mark as invalid
+		str = strings.TrimSpace(str)
+		if str == "-" || str == "" {
+			f.IsValid = false
+			return nil
+		}
+		// Numbers sent as strings, e.g. "1000"
+		if num, err := strconv.ParseFloat(str, 64); err == nil {
+			f.Value = num
+			f.IsValid = true
+			return nil
+		}
+		// Otherwise it's an unexpected string
+		return fmt.Errorf("unexpected string value for numeric field: %s", str)
+	}
+
+	return fmt.Errorf("cannot unmarshal as number or string")
+}
+
+// MarshalJSON writes the value as a number, or "-" when it is not set
+func (f FlexibleFloat) MarshalJSON() ([]byte, error) {
+	if !f.IsValid {
+		return []byte(`"-"`), nil
+	}
+	return json.Marshal(f.Value)
+}
+
+// UsageInfo represents the usage data from /api/v1/usage
+type UsageInfo struct {
+	UpdatedAt   string        `json:"updated_at"`
+	PeriodStart string        `json:"period_start,omitempty"`
+	PeriodEnd   string        `json:"period_end,omitempty"`
+	Points      FlexibleFloat `json:"points"`
+	TotalPoints FlexibleFloat `json:"total_points"`
+	ContextLen  float64       `json:"context_length"`
+}
+
+// PercentUsed returns Points as a percentage of TotalPoints, rounded to one
+// decimal. ok is false when either value is unknown or the total is zero.
+func (u *UsageInfo) PercentUsed() (percent float64, ok bool) {
+	if !u.Points.IsValid || !u.TotalPoints.IsValid || u.TotalPoints.Value <= 0 {
+		return 0, false
+	}
+	return math.Round(u.Points.Value/u.TotalPoints.Value*1000) / 10, true
+}
+
+// GetUsage fetches the current usage summary
+func GetUsage(ctx context.Context) (*UsageInfo, error) {
+	var usage UsageInfo
+	if err := getJSON(ctx, "/api/v1/usage", nil, &usage); err != nil {
+		return nil, err
+	}
+	return &usage, nil
+}
+
+// UsageHistoryQuery selects a time range and grouping for usage history
+type UsageHistoryQuery struct {
+	From    time.Time
+	To      time.Time
+	GroupBy string
+}
+
+// UsageEntry is one group (a day, model, integration or project) of usage history
+type UsageEntry struct {
+	Key      string  `json:"key"`
+	Points   float64 `json:"points"`
+	Requests int     `json:"requests"`
+}
+
+// UsageHistory represents the response from /api/v1/usage/history
+type UsageHistory struct {
+	From        string       `json:"from"`
+	To          string       `json:"to"`
+	GroupBy     string       `json:"group_by"`
+	Entries     []UsageEntry `json:"entries"`
+	TotalPoints float64      `json:"total_points"`
+}
+
+// GetUsageHistory fetches usage for a time range, grouped by the requested key
+func GetUsageHistory(ctx context.Context, q UsageHistoryQuery) (*UsageHistory, error) {
+	query := url.Values{}
+	query.Set("from", q.From.Format("2006-01-02"))
+	query.Set("to", q.To.Format("2006-01-02"))
+	query.Set("group_by", q.GroupBy)
+
+	var history UsageHistory
+	if err := getJSON(ctx, "/api/v1/usage/history", query, &history); err != nil {
+		return nil, err
+	}
+	return &history, nil
+}
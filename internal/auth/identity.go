@@ -0,0 +1,80 @@
+package auth
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/costa-app/costa-cli/internal/safefile"
+)
+
+// Organization is the Costa organization an account acts on behalf of
+type Organization struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Slug string `json:"slug,omitempty"`
+}
+
+// Identity is the account the CLI is logged in as, from the user-info endpoint
+type Identity struct {
+	FetchedAt time.Time     `json:"fetched_at"`
+	Org       *Organization `json:"org,omitempty"`
+	Email     string        `json:"email"`
+	Name      string        `json:"name,omitempty"`
+	Plan      string        `json:"plan,omitempty"`
+	// SelectedOrgID is the organization selected when the identity was fetched
+	SelectedOrgID string   `json:"selected_org_id,omitempty"`
+	Scopes        []string `json:"scopes,omitempty"`
+}
+
+// GetIdentityPath returns the path to the cached identity file, kept next to the token metadata
+func GetIdentityPath() (string, error) {
+	configDir, err := GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "identity.json"), nil
+}
+
+// SaveIdentity caches the logged-in identity for the selected organization
+func SaveIdentity(identity *Identity) error {
+	path, err := GetIdentityPath()
+	if err != nil {
+		return err
+	}
+	identity.SelectedOrgID = SelectedOrgID()
+	data, err := json.MarshalIndent(identity, "", "  ")
+	if err != nil {
+		return err
+	}
+	return safefile.Write(path, data, 0600)
+}
+
+// LoadIdentity returns the cached identity, or nil if none has been fetched
+// yet for the selected organization
+func LoadIdentity() *Identity {
+	path, err := GetIdentityPath()
+	if err != nil {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var identity Identity
+	if err := json.Unmarshal(data, &identity); err != nil {
+		return nil
+	}
+	if identity.SelectedOrgID != SelectedOrgID() {
+		return nil
+	}
+	return &identity
+}
+
+// deleteIdentity removes the cached identity
+func deleteIdentity() {
+	if path, err := GetIdentityPath(); err == nil {
+		_ = os.Remove(path)
+	}
+}
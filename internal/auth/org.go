@@ -0,0 +1,140 @@
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/zalando/go-keyring"
+
+	"github.com/costa-app/costa-cli/internal/config"
+	"github.com/costa-app/costa-cli/internal/debug"
+	"github.com/costa-app/costa-cli/internal/safefile"
+)
+
+// OrgHeader scopes API and coding token requests to the selected organization
+const OrgHeader = "X-Costa-Organization"
+
+// orgSelections maps profile names to their selected organization
+type orgSelections map[string]*Organization
+
+// GetOrgPath returns the path to the per-profile organization selection file
+func GetOrgPath() (string, error) {
+	configDir, err := GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "org.json"), nil
+}
+
+func loadOrgSelections() (orgSelections, error) {
+	path, err := GetOrgPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return orgSelections{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	selections := orgSelections{}
+	if err := json.Unmarshal(data, &selections); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return selections, nil
+}
+
+// SelectedOrg returns the organization selected for the active profile, or nil
+// when requests use the account's default organization
+func SelectedOrg() *Organization {
+	selections, err := loadOrgSelections()
+	if err != nil {
+		debug.Printf("Failed to load organization selection: %v\n", err)
+		return nil
+	}
+	profile, err := config.CurrentProfile()
+	if err != nil {
+		debug.Printf("Failed to resolve profile: %v\n", err)
+		return nil
+	}
+	return selections[profile]
+}
+
+// SelectedOrgID returns the ID of the organization selected for the active
+// profile, or "" for the account's default. Tokens and cached state record
+// it, so they can tell when they belong to another organization.
+func SelectedOrgID() string {
+	if org := SelectedOrg(); org != nil {
+		return org.ID
+	}
+	return ""
+}
+
+// SelectOrg persists the organization for the active profile; nil clears it.
+// The coding token and cached identity belong to the previous organization,
+// so they are dropped and fetched again on next use.
+func SelectOrg(org *Organization) error {
+	profile, err := config.CurrentProfile()
+	if err != nil {
+		return err
+	}
+	selections, err := loadOrgSelections()
+	if err != nil {
+		return err
+	}
+	if org == nil {
+		delete(selections, profile)
+	} else {
+		selections[profile] = org
+	}
+
+	path, err := GetOrgPath()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(selections, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := safefile.Write(path, data, 0600); err != nil {
+		return err
+	}
+
+	deleteIdentity()
+	return clearCodingToken()
+}
+
+// SetOrgHeader adds the selected organization to an API request
+func SetOrgHeader(req *http.Request) {
+	if orgID := SelectedOrgID(); orgID != "" {
+		req.Header.Set(OrgHeader, orgID)
+	}
+}
+
+// clearCodingToken drops the stored coding token so GetCodingToken fetches a new one
+func clearCodingToken() error {
+	tokenMutex.Lock()
+	defer tokenMutex.Unlock()
+
+	token, err := LoadToken()
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("failed to load token: %w", err)
+	}
+	if token.Coding == nil && token.CLI == nil {
+		return nil
+	}
+	token.Coding = nil
+	token.CLI = nil
+	if useKeyring {
+		_ = keyring.Delete(keyringService, keyringCodingAccessToken)
+	}
+	return SaveToken(token)
+}
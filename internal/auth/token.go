@@ -3,6 +3,7 @@ package auth
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -14,7 +15,10 @@ import (
 	"github.com/zalando/go-keyring"
 	"golang.org/x/oauth2"
 
+	"github.com/costa-app/costa-cli/internal/config"
 	"github.com/costa-app/costa-cli/internal/debug"
+	"github.com/costa-app/costa-cli/internal/httpclient"
+	"github.com/costa-app/costa-cli/internal/safefile"
 )
 
 const (
@@ -30,6 +34,9 @@ const (
 	keyringCodingAccessToken = "coding-access-token" // #nosec G101
 )
 
+// ErrLoginRequired is wrapped by errors that can only be resolved by logging in
+var ErrLoginRequired = errors.New("please login first")
+
 var (
 	// tokenMutex guards against concurrent refresh/fetch operations
 	tokenMutex sync.Mutex
@@ -44,6 +51,8 @@ type TokenData struct {
 	AccessToken  string     `json:"access_token"`
 	RefreshToken string     `json:"refresh_token,omitempty"`
 	TokenType    string     `json:"token_type"`
+	// OrgID is the organization a coding token was issued for; empty for the account's default
+	OrgID string `json:"org_id,omitempty"`
 }
 
 // IsExpiredWithSkew returns true if the token is expired or will expire within the skew window
@@ -76,16 +85,12 @@ type TokenMetadata struct {
 	OAuthTokenType  string     `json:"oauth_token_type,omitempty"`
 	CodingExpiresAt *time.Time `json:"coding_expires_at,omitempty"`
 	CodingTokenType string     `json:"coding_token_type,omitempty"`
+	CodingOrgID     string     `json:"coding_org_id,omitempty"`
 }
 
 // GetConfigDir returns the costa config directory path
 func GetConfigDir() (string, error) {
-	home, err := os.UserHomeDir()
-	if err != nil {
-		return "", err
-	}
-	configDir := filepath.Join(home, ".config", "costa")
-	return configDir, nil
+	return config.Dir()
 }
 
 // GetTokenPath returns the path to the token file (legacy)
@@ -166,6 +171,7 @@ func saveTokenToKeyring(token *Token) error {
 	if token.Coding != nil {
 		metadata.CodingExpiresAt = token.Coding.ExpiresAt
 		metadata.CodingTokenType = token.Coding.TokenType
+		metadata.CodingOrgID = token.Coding.OrgID
 	}
 
 	metadataPath, err := GetMetadataPath()
@@ -178,7 +184,7 @@ func saveTokenToKeyring(token *Token) error {
 		return err
 	}
 
-	return os.WriteFile(metadataPath, data, 0600)
+	return safefile.Write(metadataPath, data, 0600)
 }
 
 // saveTokenToFile saves the entire token to a file (fallback method)
@@ -195,7 +201,7 @@ func saveTokenToFile(token *Token) error {
 		return err
 	}
 
-	if err := os.WriteFile(tokenPath, data, 0600); err != nil {
+	if err := safefile.Write(tokenPath, data, 0600); err != nil {
 		debug.Printf("Failed to write token file: %v\n", err)
 		return err
 	}
@@ -281,6 +287,7 @@ func loadTokenFromKeyring() (*Token, error) {
 				AccessToken: codingAccess,
 				TokenType:   metadata.CodingTokenType,
 				ExpiresAt:   metadata.CodingExpiresAt,
+				OrgID:       metadata.CodingOrgID,
 			}
 		}
 	}
@@ -336,9 +343,83 @@ func DeleteToken() error {
 		_ = os.Remove(tokenPath)
 	}
 
+	// The cached identity belongs to the removed tokens
+	deleteIdentity()
+
 	return nil
 }
 
+// OAuthExpiry returns when the stored OAuth token expires, or nil if unknown.
+// It reads only the metadata or fallback token file, never the keyring.
+func OAuthExpiry() *time.Time {
+	metadata := loadTokenMetadata()
+	if metadata == nil {
+		return nil
+	}
+	return metadata.OAuthExpiresAt
+}
+
+// CodingExpiry returns when the stored coding token expires, or nil if unknown.
+// Like OAuthExpiry it never touches the keyring.
+func CodingExpiry() *time.Time {
+	metadata := loadTokenMetadata()
+	if metadata == nil {
+		return nil
+	}
+	return metadata.CodingExpiresAt
+}
+
+// loadTokenMetadata returns token expiry metadata from the fallback token file
+// or the keyring-mode metadata file, whichever is in use
+func loadTokenMetadata() *TokenMetadata {
+	if tokenPath, err := GetTokenPath(); err == nil {
+		if _, statErr := os.Stat(tokenPath); statErr == nil {
+			token, err := loadTokenFromFile()
+			if err != nil {
+				return nil
+			}
+			metadata := &TokenMetadata{}
+			if token.OAuth != nil {
+				metadata.OAuthExpiresAt = token.OAuth.ExpiresAt
+				metadata.OAuthTokenType = token.OAuth.TokenType
+			}
+			if token.Coding != nil {
+				metadata.CodingExpiresAt = token.Coding.ExpiresAt
+				metadata.CodingTokenType = token.Coding.TokenType
+				metadata.CodingOrgID = token.Coding.OrgID
+			}
+			return metadata
+		}
+	}
+
+	metadataPath, err := GetMetadataPath()
+	if err != nil {
+		return nil
+	}
+	data, err := os.ReadFile(metadataPath)
+	if err != nil {
+		return nil
+	}
+	var metadata TokenMetadata
+	if err := json.Unmarshal(data, &metadata); err != nil {
+		return nil
+	}
+	return &metadata
+}
+
+// HasStoredToken reports whether a token or token metadata file exists.
+// Unlike IsLoggedIn it never reads the keyring, so it is cheap enough for shell prompts.
+func HasStoredToken() bool {
+	for _, pathFn := range []func() (string, error){GetTokenPath, GetMetadataPath} {
+		if path, err := pathFn(); err == nil {
+			if _, err := os.Stat(path); err == nil {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // IsLoggedIn checks if a token exists
 func IsLoggedIn() bool {
 	debug.Printf("Checking if logged in...\n")
@@ -388,11 +469,14 @@ func EnsureOAuthTokenValid(ctx context.Context) (*TokenData, error) {
 	// Load current token
 	token, err := LoadToken()
 	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("no token found: %w", ErrLoginRequired)
+		}
 		return nil, fmt.Errorf("failed to load token: %w", err)
 	}
 
 	if token.OAuth == nil {
-		return nil, fmt.Errorf("no OAuth token found, please login first")
+		return nil, fmt.Errorf("no OAuth token found: %w", ErrLoginRequired)
 	}
 
 	// Check if refresh is needed
@@ -405,11 +489,15 @@ func EnsureOAuthTokenValid(ctx context.Context) (*TokenData, error) {
 
 	// Check if we have a refresh token
 	if token.OAuth.RefreshToken == "" {
-		return nil, fmt.Errorf("OAuth token expired and no refresh token available, please login again")
+		return nil, fmt.Errorf("OAuth token expired and no refresh token available: %w", ErrLoginRequired)
 	}
 
-	// Perform refresh
-	config := OAuthConfig()
+	// Perform refresh using the configured proxy/TLS settings
+	oauthConfig := OAuthConfig()
+	refreshCtx, err := httpclient.Context(ctx)
+	if err != nil {
+		return nil, err
+	}
 
 	// Create token source for refresh
 	oldToken := &oauth2.Token{
@@ -421,7 +509,7 @@ func EnsureOAuthTokenValid(ctx context.Context) (*TokenData, error) {
 		oldToken.Expiry = *token.OAuth.ExpiresAt
 	}
 
-	tokenSource := config.TokenSource(ctx, oldToken)
+	tokenSource := oauthConfig.TokenSource(refreshCtx, oldToken)
 	newToken, err := tokenSource.Token()
 	if err != nil {
 		return nil, fmt.Errorf("failed to refresh OAuth token: %w", err)
@@ -474,8 +562,10 @@ func GetCodingToken(ctx context.Context) (*TokenData, error) {
 		return nil, fmt.Errorf("failed to load token: %w", err)
 	}
 
-	// Check if we have a valid coding token
-	if token.Coding != nil && token.Coding.IsValid() {
+	// Check if we have a valid coding token for the selected organization.
+	// The selection is per profile, so a profile switch can change it.
+	orgID := SelectedOrgID()
+	if token.Coding != nil && token.Coding.IsValid() && token.Coding.OrgID == orgID {
 		debug.Printf("Coding token is valid (expires: %v)\n", token.Coding.ExpiresAt)
 		return token.Coding, nil
 	}
@@ -490,8 +580,14 @@ func GetCodingToken(ctx context.Context) (*TokenData, error) {
 
 	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", oauthToken.AccessToken))
 	req.Header.Set("Accept", "application/json")
+	if orgID != "" {
+		req.Header.Set(OrgHeader, orgID)
+	}
 
-	client := &http.Client{Timeout: 30 * time.Second}
+	client, err := httpclient.New(30 * time.Second)
+	if err != nil {
+		return nil, err
+	}
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch coding token: %w", err)
@@ -501,7 +597,7 @@ func GetCodingToken(ctx context.Context) (*TokenData, error) {
 	debug.Printf("Coding token response: HTTP %d\n", resp.StatusCode)
 
 	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
-		return nil, fmt.Errorf("authentication failed: HTTP %d - please login again", resp.StatusCode)
+		return nil, fmt.Errorf("authentication failed: HTTP %d: %w", resp.StatusCode, ErrLoginRequired)
 	}
 
 	if resp.StatusCode == http.StatusNotFound {
@@ -536,6 +632,7 @@ func GetCodingToken(ctx context.Context) (*TokenData, error) {
 		AccessToken: codingResp.Token,
 		TokenType:   "Bearer", // Default to Bearer since API doesn't return token_type
 		ExpiresAt:   expiresAt,
+		OrgID:       orgID,
 	}
 
 	if err := SaveToken(token); err != nil {
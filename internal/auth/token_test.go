@@ -1,6 +1,9 @@
 package auth
 
 import (
+	"context"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"testing"
@@ -306,6 +309,96 @@ func TestIsLoggedIn(t *testing.T) {
 	}
 }
 
+func TestGetCodingToken_SwitchingProfileChangesOrg(t *testing.T) {
+	useKeyring = false
+	defer func() { useKeyring = true }()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		org := r.Header.Get(OrgHeader)
+		if org == "" {
+			org = "default"
+		}
+		_, _ = w.Write([]byte(`{"token": "coding-` + org + `"}`))
+	}))
+	defer server.Close()
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("COSTA_BASE_URL", server.URL)
+
+	expiresAt := time.Now().Add(time.Hour)
+	if err := SaveToken(&Token{OAuth: &TokenData{AccessToken: "oauth", TokenType: "Bearer", ExpiresAt: &expiresAt}}); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("COSTA_PROFILE", "work")
+	if err := SelectOrg(&Organization{ID: "42", Name: "Work"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := SaveIdentity(&Identity{Email: "dev@example.com"}); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	for _, step := range []struct {
+		profile string
+		want    string
+	}{
+		{"", "coding-default"},
+		{"work", "coding-42"},
+		{"work", "coding-42"},
+		{"", "coding-default"},
+	} {
+		t.Setenv("COSTA_PROFILE", step.profile)
+		token, err := GetCodingToken(ctx)
+		if err != nil {
+			t.Fatalf("GetCodingToken(%q): %v", step.profile, err)
+		}
+		if token.AccessToken != step.want {
+			t.Errorf("profile %q: expected %s, got %s", step.profile, step.want, token.AccessToken)
+		}
+	}
+
+	// The identity was fetched for the work profile's organization
+	if identity := LoadIdentity(); identity != nil {
+		t.Errorf("expected no identity for the default profile, got %+v", identity)
+	}
+	t.Setenv("COSTA_PROFILE", "work")
+	if identity := LoadIdentity(); identity == nil || identity.Email != "dev@example.com" {
+		t.Errorf("expected the work profile's identity, got %+v", identity)
+	}
+}
+
+func TestSelectOrg_BrokenConfig(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	configDir := filepath.Join(home, ".config", "costa")
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(configDir, "config.toml"), []byte("profile = \n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	// Without COSTA_PROFILE the profile is unknown, so nothing may be written
+	t.Setenv("COSTA_PROFILE", "")
+	if err := SelectOrg(&Organization{ID: "42"}); err == nil {
+		t.Fatal("expected an error for an unreadable config")
+	}
+	if _, err := os.Stat(filepath.Join(configDir, "org.json")); !os.IsNotExist(err) {
+		t.Errorf("expected no organization selection to be written, got %v", err)
+	}
+
+	t.Setenv("COSTA_PROFILE", "work")
+	if err := SelectOrg(&Organization{ID: "42"}); err != nil {
+		t.Fatal(err)
+	}
+	selections, err := loadOrgSelections()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if org := selections["work"]; org == nil || org.ID != "42" || len(selections) != 1 {
+		t.Errorf("expected the selection under the work profile, got %+v", selections)
+	}
+}
+
 func TestGetConfigDir(t *testing.T) {
 	configDir, err := GetConfigDir()
 	if err != nil {
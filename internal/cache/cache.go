@@ -0,0 +1,133 @@
+package cache
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Dir returns the costa cache directory (e.g. ~/.cache/costa on Linux)
+func Dir() (string, error) {
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(base, "costa"), nil
+}
+
+// Path returns the path of a named cache entry
+func Path(name string) (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, name+".json"), nil
+}
+
+// Load reads a named cache entry into v.
+// Returns an error satisfying errors.Is(err, os.ErrNotExist) if the entry is missing.
+func Load(name string, v any) error {
+	path, err := Path(name)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// Save atomically writes v as a named cache entry, so concurrent readers
+// never observe a partially written file
+func Save(name string, v any) error {
+	path, err := Path(name)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	defer func() { _ = os.Remove(tmpPath) }()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
+
+// Remove deletes a named cache entry; a missing entry is not an error
+func Remove(name string) error {
+	path, err := Path(name)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// TryLock takes an exclusive, cross-process lock for a named entry without blocking.
+// Locks older than staleAfter are assumed abandoned by a crashed process and broken.
+// The returned unlock function must be called when ok is true.
+func TryLock(name string, staleAfter time.Duration) (unlock func(), ok bool, err error) {
+	path, err := Path(name)
+	if err != nil {
+		return nil, false, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, false, err
+	}
+	lockPath := path + ".lock"
+
+	for attempt := 0; attempt < 2; attempt++ {
+		f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+		if err == nil {
+			_ = f.Close()
+			return func() { _ = os.Remove(lockPath) }, true, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, false, err
+		}
+
+		// Break the lock if its holder has been gone for too long
+		info, statErr := os.Stat(lockPath)
+		if statErr != nil || time.Since(info.ModTime()) < staleAfter {
+			return nil, false, nil
+		}
+		_ = os.Remove(lockPath)
+	}
+	return nil, false, nil
+}
+
+// IsLocked reports whether a live lock is held for a named entry
+func IsLocked(name string, staleAfter time.Duration) bool {
+	path, err := Path(name)
+	if err != nil {
+		return false
+	}
+	info, err := os.Stat(path + ".lock")
+	return err == nil && time.Since(info.ModTime()) < staleAfter
+}
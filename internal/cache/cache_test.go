@@ -0,0 +1,72 @@
+package cache
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestSaveLoad(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	var missing map[string]int
+	if err := Load("entry", &missing); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected not-exist error for missing entry, got %v", err)
+	}
+
+	if err := Save("entry", map[string]int{"points": 42}); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	var got map[string]int
+	if err := Load("entry", &got); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if got["points"] != 42 {
+		t.Errorf("expected points 42, got %v", got)
+	}
+
+	if err := Remove("entry"); err != nil {
+		t.Fatalf("Remove failed: %v", err)
+	}
+	if err := Remove("entry"); err != nil {
+		t.Errorf("Remove of missing entry should succeed, got %v", err)
+	}
+}
+
+func TestTryLock(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	unlock, ok, err := TryLock("entry", time.Minute)
+	if err != nil || !ok {
+		t.Fatalf("expected first lock to succeed, got ok=%v err=%v", ok, err)
+	}
+	if !IsLocked("entry", time.Minute) {
+		t.Error("expected entry to be locked")
+	}
+
+	if _, ok, _ := TryLock("entry", time.Minute); ok {
+		t.Error("expected second lock to fail while held")
+	}
+
+	unlock()
+	if IsLocked("entry", time.Minute) {
+		t.Error("expected entry to be unlocked")
+	}
+}
+
+func TestTryLock_BreaksStaleLock(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	if _, ok, _ := TryLock("entry", time.Minute); !ok {
+		t.Fatal("expected first lock to succeed")
+	}
+
+	// A zero timeout treats the abandoned lock as stale
+	unlock, ok, err := TryLock("entry", 0)
+	if err != nil || !ok {
+		t.Fatalf("expected stale lock to be broken, got ok=%v err=%v", ok, err)
+	}
+	unlock()
+}
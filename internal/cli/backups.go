@@ -0,0 +1,342 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/costa-app/costa-cli/internal/config"
+	"github.com/costa-app/costa-cli/internal/diff"
+	"github.com/costa-app/costa-cli/internal/integrations"
+)
+
+var (
+	backupsRestoreBackupDir string
+	backupsRestoreForce     bool
+	backupsPruneApp         string
+	backupsPruneOlderThan   string
+	backupsPruneKeep        int
+	backupsPruneDryRun      bool
+)
+
+// backupsListResult is the output of 'costa backups list'
+type backupsListResult struct {
+	Status  string                  `json:"status"`
+	Backups []integrations.Manifest `json:"backups"`
+}
+
+func (r backupsListResult) table() ([]string, [][]string) {
+	headers := []string{"ID", "APP", "SCOPE", "CREATED", "SOURCE"}
+	var rows [][]string
+	for _, b := range r.Backups {
+		rows = append(rows, []string{b.ID, b.App, string(b.Scope), b.Created.Local().Format(time.DateTime), b.SourcePath})
+	}
+	return headers, rows
+}
+
+func (r backupsListResult) printHuman(out io.Writer) error {
+	if len(r.Backups) == 0 {
+		fmt.Fprintln(out, "No backups found.")
+		return nil
+	}
+	if err := writeTable(out, r); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "\nRun 'costa backups diff <id>' to compare a backup with the current file.\n")
+	return nil
+}
+
+// backupsDiffResult is the output of 'costa backups diff'
+type backupsDiffResult struct {
+	Status     string `json:"status"`
+	ID         string `json:"id"`
+	SourcePath string `json:"source_path"`
+	BackupPath string `json:"backup_path"`
+	// Diff is a unified diff from the backup to the current file
+	Diff    string `json:"diff"`
+	Changed bool   `json:"changed"`
+}
+
+func (r backupsDiffResult) printHuman(out io.Writer) error {
+	if !r.Changed {
+		fmt.Fprintf(out, "✓ %s matches backup %s\n", r.SourcePath, r.ID)
+		return nil
+	}
+	fmt.Fprint(out, r.Diff)
+	return nil
+}
+
+// backupsRestoreResult is the output of 'costa backups restore'
+type backupsRestoreResult struct {
+	Status     string `json:"status"`
+	ID         string `json:"id"`
+	SourcePath string `json:"source_path"`
+	// BackupPath is the backup of the file as it was before the restore
+	BackupPath string `json:"backup_path,omitempty"`
+	Changed    bool   `json:"changed"`
+}
+
+func (r backupsRestoreResult) printHuman(out io.Writer) error {
+	if !r.Changed {
+		fmt.Fprintf(out, "✓ %s already matches backup %s\n", r.SourcePath, r.ID)
+		return nil
+	}
+	if r.BackupPath != "" {
+		fmt.Fprintf(out, "💾 Previous contents backed up to %s\n", r.BackupPath)
+	}
+	fmt.Fprintf(out, "✅ Restored %s from %s\n", r.SourcePath, r.ID)
+	return nil
+}
+
+// backupsPruneResult is the output of 'costa backups prune'
+type backupsPruneResult struct {
+	Status  string                  `json:"status"`
+	Removed []integrations.Manifest `json:"removed"`
+	DryRun  bool                    `json:"dry_run"`
+}
+
+func (r backupsPruneResult) printHuman(out io.Writer) error {
+	verb := "Removed"
+	if r.DryRun {
+		verb = "Would remove"
+	}
+	for _, b := range r.Removed {
+		fmt.Fprintf(out, "  %s (%s)\n", b.ID, b.BackupPath)
+	}
+	fmt.Fprintf(out, "%s %d backup(s)\n", verb, len(r.Removed))
+	return nil
+}
+
+var backupsCmd = &cobra.Command{
+	Use:   "backups",
+	Short: "List, compare, restore and prune config backups",
+	Long: `Manage the backups 'costa setup' makes before changing a tool's config.
+
+Backups live in ~/.config/costa/backups/<app>, each with a manifest recording
+the source file, app, scope and CLI version. Backups written to a custom
+--backup-dir are not listed.`,
+}
+
+var backupsListCmd = &cobra.Command{
+	Use:   "list [app]",
+	Short: "List backups, newest first",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		app := ""
+		if len(args) > 0 {
+			reg, err := lookupIntegration(args[0])
+			if err != nil {
+				return err
+			}
+			app = reg.Name
+		}
+
+		backups, err := integrations.ListBackups(app)
+		if err != nil {
+			return fmt.Errorf("failed to list backups: %w", err)
+		}
+		return printResult(cmd, backupsListResult{Status: "ok", Backups: append([]integrations.Manifest{}, backups...)})
+	},
+}
+
+var backupsDiffCmd = &cobra.Command{
+	Use:   "diff <id>",
+	Short: "Show how the current file differs from a backup",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		backup, err := findBackup(args[0])
+		if err != nil {
+			return err
+		}
+		text, changed, err := diffBackup(backup)
+		if err != nil {
+			return err
+		}
+		return printResult(cmd, backupsDiffResult{
+			Status:     "ok",
+			ID:         backup.ID,
+			SourcePath: backup.SourcePath,
+			BackupPath: backup.BackupPath,
+			Diff:       text,
+			Changed:    changed,
+		})
+	},
+}
+
+var backupsRestoreCmd = &cobra.Command{
+	Use:   "restore <id>",
+	Short: "Restore a file from a backup",
+	Long: `Write a backup's contents back to the file it was taken from. The file's
+current contents are backed up first, so a restore can be undone.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runBackupsRestore,
+}
+
+var backupsPruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Delete old backups",
+	Long: `Delete backups beyond the newest --keep of each config file that are older
+than --older-than. Without flags, the [backups] retention policy from the
+config file is used.`,
+	Example: `  costa backups prune --keep 5
+  costa backups prune --older-than 30d --dry-run`,
+	Args: cobra.NoArgs,
+	RunE: runBackupsPrune,
+}
+
+func init() {
+	backupsRestoreCmd.Flags().BoolVar(&backupsRestoreForce, "force", false, "Skip confirmation prompt (auto-yes)")
+	backupsRestoreCmd.Flags().StringVar(&backupsRestoreBackupDir, "backup-dir", "", "Directory for the backup of the current file (default: ~/.config/costa/backups/<app>)")
+
+	backupsPruneCmd.Flags().IntVar(&backupsPruneKeep, "keep", 0, "Always keep this many newest backups of each config file")
+	backupsPruneCmd.Flags().StringVar(&backupsPruneOlderThan, "older-than", "", "Only delete backups older than this, e.g. 30d or 12h")
+	backupsPruneCmd.Flags().StringVar(&backupsPruneApp, "app", "", "Only prune backups of this app")
+	backupsPruneCmd.Flags().BoolVar(&backupsPruneDryRun, "dry-run", false, "Show what would be deleted without deleting")
+
+	backupsCmd.AddCommand(backupsListCmd)
+	backupsCmd.AddCommand(backupsDiffCmd)
+	backupsCmd.AddCommand(backupsRestoreCmd)
+	backupsCmd.AddCommand(backupsPruneCmd)
+}
+
+// findBackup looks up a backup by ID, reporting unknown IDs as usage errors
+func findBackup(id string) (integrations.Manifest, error) {
+	backup, err := integrations.FindBackup(id)
+	if errors.Is(err, integrations.ErrBackupNotFound) {
+		return backup, &CLIError{
+			Code:    ErrCodeUsage,
+			Message: fmt.Sprintf("unknown backup: %s", id),
+			Hint:    "Run 'costa backups list' to see available backups.",
+		}
+	}
+	return backup, err
+}
+
+// diffBackup returns a unified diff from a backup to its current source
+// file, and whether they differ
+func diffBackup(backup integrations.Manifest) (string, bool, error) {
+	old, current, changed, err := readBackup(backup)
+	if err != nil {
+		return "", false, err
+	}
+	return diff.Unified(backup.BackupPath, backup.SourcePath, old, current, 3), changed, nil
+}
+
+// readBackup returns the contents of a backup and of its current source
+// file, with the tokens found in either redacted, and whether they differ
+func readBackup(backup integrations.Manifest) (old, current string, changed bool, err error) {
+	oldData, err := os.ReadFile(backup.BackupPath)
+	if err != nil {
+		return "", "", false, fmt.Errorf("failed to read backup: %w", err)
+	}
+	currentData, err := os.ReadFile(backup.SourcePath)
+	if err != nil && !os.IsNotExist(err) {
+		return "", "", false, fmt.Errorf("failed to read %s: %w", backup.SourcePath, err)
+	}
+
+	var secrets []string
+	if reg, ok := integrations.Lookup(backup.App); ok && reg.Secrets != nil {
+		secrets = append(reg.Secrets(oldData), reg.Secrets(currentData)...)
+	}
+	old = integrations.Redact(string(oldData), secrets...)
+	current = integrations.Redact(string(currentData), secrets...)
+	return old, current, !bytes.Equal(oldData, currentData), nil
+}
+
+func runBackupsRestore(cmd *cobra.Command, args []string) error {
+	backup, err := findBackup(args[0])
+	if err != nil {
+		return err
+	}
+
+	// Machine-readable output keeps stdout for the result
+	out := cmd.OutOrStdout()
+	if outputFormat(cmd) != "" {
+		out = cmd.ErrOrStderr()
+	}
+
+	old, current, changed, err := readBackup(backup)
+	if err != nil {
+		return err
+	}
+	result := backupsRestoreResult{Status: "ok", ID: backup.ID, SourcePath: backup.SourcePath, Changed: changed}
+	if !result.Changed {
+		return printResult(cmd, result)
+	}
+
+	if !backupsRestoreForce {
+		// Show what the restore undoes: the current file back to the backup
+		fmt.Fprint(out, diff.Unified(backup.SourcePath, backup.BackupPath, current, old, 3))
+		fmt.Fprintf(out, "\nRestore %s from %s? [Y/n]: ", backup.SourcePath, backup.ID)
+		response, _ := bufio.NewReader(cmd.InOrStdin()).ReadString('\n')
+		resp := strings.ToLower(strings.TrimSpace(response))
+		if resp == "n" || resp == "no" { // default YES
+			fmt.Fprintln(out, "Canceled.")
+			return errCanceled
+		}
+	}
+
+	result.BackupPath, err = integrations.RestoreBackup(backup, backupsRestoreBackupDir)
+	if err != nil {
+		return fmt.Errorf("failed to restore %s: %w", backup.SourcePath, err)
+	}
+	return printResult(cmd, result)
+}
+
+func runBackupsPrune(cmd *cobra.Command, args []string) error {
+	opts := integrations.PruneOpts{Keep: backupsPruneKeep, DryRun: backupsPruneDryRun}
+
+	if backupsPruneApp != "" {
+		reg, err := lookupIntegration(backupsPruneApp)
+		if err != nil {
+			return err
+		}
+		opts.App = reg.Name
+	}
+
+	if backupsPruneOlderThan != "" {
+		d, err := parseSince(backupsPruneOlderThan)
+		if err != nil {
+			return &CLIError{Code: ErrCodeUsage, Message: fmt.Sprintf("invalid --older-than: %s", backupsPruneOlderThan), Hint: "Use e.g. 30d, 4w or 12h.", Err: err}
+		}
+		opts.OlderThan = d
+	}
+
+	// Fall back to the configured retention policy
+	if !cmd.Flags().Changed("keep") && !cmd.Flags().Changed("older-than") {
+		cfg, err := config.Load()
+		if err != nil {
+			return err
+		}
+		if cfg.Backups.Keep <= 0 && cfg.Backups.MaxAgeDays <= 0 {
+			return &CLIError{
+				Code:    ErrCodeUsage,
+				Message: "no retention policy given",
+				Hint:    "Pass --keep and/or --older-than, or set [backups] keep / max_age_days in the config file.",
+			}
+		}
+		opts.Keep = cfg.Backups.Keep
+		opts.OlderThan = time.Duration(cfg.Backups.MaxAgeDays) * 24 * time.Hour
+	}
+
+	if opts.Keep < 0 {
+		return &CLIError{Code: ErrCodeUsage, Message: "--keep must not be negative"}
+	}
+
+	removed, err := integrations.PruneBackups(opts)
+	if err != nil {
+		return fmt.Errorf("failed to prune backups: %w", err)
+	}
+	return printResult(cmd, backupsPruneResult{
+		Status:  "ok",
+		Removed: append([]integrations.Manifest{}, removed...),
+		DryRun:  opts.DryRun,
+	})
+}
@@ -0,0 +1,203 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/costa-app/costa-cli/internal/integrations"
+)
+
+func runBackupsCommand(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	defer func() {
+		for _, c := range backupsCmd.Commands() {
+			c.Flags().VisitAll(func(f *pflag.Flag) {
+				_ = f.Value.Set(f.DefValue)
+				f.Changed = false
+			})
+		}
+	}()
+
+	var buf bytes.Buffer
+	root := &cobra.Command{Use: "costa", SilenceErrors: true, SilenceUsage: true}
+	root.PersistentFlags().StringP(outputFlagName, "o", "", outputFlagUsage)
+	root.AddCommand(backupsCmd)
+	root.SetOut(&buf)
+	root.SetErr(&buf)
+	root.SetArgs(append([]string{"backups"}, args...))
+	err := root.Execute()
+	return buf.String(), err
+}
+
+// setupBackup backs up a settings file and then changes it
+func setupBackup(t *testing.T) (string, integrations.Manifest) {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	source := filepath.Join(home, ".claude", "settings.json")
+	if err := os.MkdirAll(filepath.Dir(source), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(source, []byte("{\n  \"model\": \"opus\"\n}\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := integrations.CreateBackup("claude-code", integrations.ScopeUser, source, ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(source, []byte("{\n  \"model\": \"costa/auto\"\n}\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	backups, err := integrations.ListBackups("")
+	if err != nil || len(backups) != 1 {
+		t.Fatalf("expected one backup, got %v (%v)", backups, err)
+	}
+	return source, backups[0]
+}
+
+func TestBackupsList_JSON(t *testing.T) {
+	source, backup := setupBackup(t)
+
+	out, err := runBackupsCommand(t, "list", "claude", "-o", "json")
+	if err != nil {
+		t.Fatalf("list failed: %v\n%s", err, out)
+	}
+	var result struct {
+		Backups []integrations.Manifest `json:"backups"`
+	}
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if len(result.Backups) != 1 || result.Backups[0].ID != backup.ID || result.Backups[0].SourcePath != source {
+		t.Errorf("unexpected backups %+v", result.Backups)
+	}
+}
+
+func TestBackupsDiffAndRestore(t *testing.T) {
+	source, backup := setupBackup(t)
+
+	out, err := runBackupsCommand(t, "diff", backup.ID)
+	if err != nil {
+		t.Fatalf("diff failed: %v\n%s", err, out)
+	}
+	if !strings.Contains(out, `-  "model": "opus"`) || !strings.Contains(out, `+  "model": "costa/auto"`) {
+		t.Errorf("unexpected diff:\n%s", out)
+	}
+
+	out, err = runBackupsCommand(t, "restore", backup.ID, "--force")
+	if err != nil {
+		t.Fatalf("restore failed: %v\n%s", err, out)
+	}
+	if data, _ := os.ReadFile(source); !strings.Contains(string(data), `"opus"`) {
+		t.Errorf("expected backup restored, got %s", data)
+	}
+
+	// The pre-restore contents were backed up too
+	backups, _ := integrations.ListBackups("claude-code")
+	if len(backups) != 2 {
+		t.Errorf("expected a backup of the pre-restore file, got %d backups", len(backups))
+	}
+
+	out, err = runBackupsCommand(t, "diff", backup.ID)
+	if err != nil || !strings.Contains(out, "matches backup") {
+		t.Errorf("expected no differences after restore, got %v:\n%s", err, out)
+	}
+}
+
+func TestBackupsRestore_Declined(t *testing.T) {
+	source, backup := setupBackup(t)
+
+	backupsCmd.SetIn(strings.NewReader("n\n"))
+	defer backupsCmd.SetIn(nil)
+	_, err := runBackupsCommand(t, "restore", backup.ID)
+	if ExitCode(err) != ExitCanceled {
+		t.Errorf("expected canceled, got %v", err)
+	}
+	if data, _ := os.ReadFile(source); !strings.Contains(string(data), "costa/auto") {
+		t.Errorf("expected file unchanged, got %s", data)
+	}
+}
+
+func TestBackupsDiff_RedactsTokens(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	source := filepath.Join(home, ".claude", "settings.json")
+	if err := os.MkdirAll(filepath.Dir(source), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(source, []byte(`{"env": {"ANTHROPIC_AUTH_TOKEN": "sk-old-secret-token"}}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := integrations.CreateBackup("claude-code", integrations.ScopeUser, source, ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(source, []byte(`{"env": {"ANTHROPIC_AUTH_TOKEN": "sk-new-secret-token"}}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	backups, err := integrations.ListBackups("")
+	if err != nil || len(backups) != 1 {
+		t.Fatalf("expected one backup, got %v (%v)", backups, err)
+	}
+
+	out, err := runBackupsCommand(t, "diff", backups[0].ID, "-o", "json")
+	if err != nil {
+		t.Fatalf("diff failed: %v\n%s", err, out)
+	}
+	var result backupsDiffResult
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if !result.Changed || !strings.Contains(result.Diff, "sk-old****oken") || !strings.Contains(result.Diff, "sk-new****oken") {
+		t.Errorf("expected a redacted diff, got %+v", result)
+	}
+
+	// The restore confirmation shows the same redacted diff
+	backupsCmd.SetIn(strings.NewReader("n\n"))
+	defer backupsCmd.SetIn(nil)
+	restoreOut, _ := runBackupsCommand(t, "restore", backups[0].ID)
+	for _, text := range []string{out, restoreOut} {
+		if strings.Contains(text, "secret-token") {
+			t.Errorf("expected tokens to be redacted, got:\n%s", text)
+		}
+	}
+}
+
+func TestBackupsPrune(t *testing.T) {
+	_, backup := setupBackup(t)
+
+	if _, err := runBackupsCommand(t, "prune"); ExitCode(err) != ExitUsage {
+		t.Errorf("expected usage error without a policy, got %v", err)
+	}
+
+	out, err := runBackupsCommand(t, "prune", "--keep", "0", "--dry-run")
+	if err != nil || !strings.Contains(out, "Would remove 1 backup(s)") {
+		t.Errorf("unexpected dry-run output %v:\n%s", err, out)
+	}
+	if _, err := integrations.FindBackup(backup.ID); err != nil {
+		t.Errorf("expected dry run to keep the backup: %v", err)
+	}
+
+	if out, err := runBackupsCommand(t, "prune", "--keep", "0"); err != nil {
+		t.Fatalf("prune failed: %v\n%s", err, out)
+	}
+	if _, err := integrations.FindBackup(backup.ID); err == nil {
+		t.Error("expected backup to be removed")
+	}
+}
+
+func TestBackupsDiff_UnknownID(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	_, err := runBackupsCommand(t, "diff", "nope")
+	if ExitCode(err) != ExitUsage {
+		t.Errorf("expected usage error, got %v", err)
+	}
+}
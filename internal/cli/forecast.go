@@ -0,0 +1,279 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/costa-app/costa-cli/internal/api"
+	"github.com/costa-app/costa-cli/internal/auth"
+	"github.com/costa-app/costa-cli/internal/cache"
+	"github.com/costa-app/costa-cli/internal/debug"
+)
+
+const (
+	usageSamplesName = "usage-samples"
+	// sampleInterval is the minimum spacing between stored usage samples
+	sampleInterval = 5 * time.Minute
+	// sampleRetention is how long usage samples are kept
+	sampleRetention = 7 * 24 * time.Hour
+	// minForecastSpan is the shortest sample history a burn rate is computed from
+	minForecastSpan = 30 * time.Minute
+	// defaultForecastWindow is the burn rate window used by status and --window
+	defaultForecastWindow = 24 * time.Hour
+	// historyForecastDays is how many days of usage history the forecast falls back to
+	historyForecastDays = 7
+	sampleLockTimeout   = 5 * time.Second
+	// maxForecastHours is the longest time to exhaustion a time.Duration can hold
+	maxForecastHours = float64(math.MaxInt64 / time.Hour)
+)
+
+// Forecast sources reported in the "source" field
+const (
+	forecastSourceSamples = "samples"
+	forecastSourceHistory = "history"
+)
+
+var forecastWindow time.Duration
+
+// usageSample is a point-in-time reading of points used
+type usageSample struct {
+	Time   time.Time `json:"time"`
+	Points float64   `json:"points"`
+}
+
+// usageSamples is the on-disk list of samples, oldest first
+type usageSamples struct {
+	// OrgID is the organization the samples were measured for
+	OrgID   string        `json:"org_id,omitempty"`
+	Samples []usageSample `json:"samples"`
+}
+
+// usageForecast is a burn rate and the projected time the allowance runs out
+type usageForecast struct {
+	// ExhaustsAt is nil when usage is not growing, or so slowly that the
+	// allowance would outlast any representable time
+	ExhaustsAt          *time.Time `json:"exhausts_at,omitempty"`
+	PeriodEnd           *time.Time `json:"period_end,omitempty"`
+	Source              string     `json:"source"`
+	BurnRatePerHour     float64    `json:"burn_rate_per_hour"`
+	ExhaustsBeforeReset bool       `json:"exhausts_before_reset"`
+}
+
+// forecastResult is the output of 'costa usage forecast'
+type forecastResult struct {
+	ExhaustsAt          *time.Time `json:"exhausts_at,omitempty"`
+	PeriodEnd           *time.Time `json:"period_end,omitempty"`
+	Status              string     `json:"status"`
+	Source              string     `json:"source"`
+	Points              float64    `json:"points"`
+	TotalPoints         float64    `json:"total_points"`
+	Remaining           float64    `json:"remaining"`
+	BurnRatePerHour     float64    `json:"burn_rate_per_hour"`
+	ExhaustsBeforeReset bool       `json:"exhausts_before_reset"`
+	// window is the burn rate window, for human output only
+	window time.Duration
+}
+
+var usageForecastCmd = &cobra.Command{
+	Use:   "forecast",
+	Short: "Forecast when the points allowance runs out",
+	Long: `Compute a rolling burn rate and project when the points allowance runs out.
+
+The burn rate comes from usage samples recorded locally whenever costa fetches
+usage (e.g. by the status line). Until enough samples exist, the last 7 days
+of usage history are used instead.`,
+	Example: `  costa usage forecast
+  costa usage forecast --window 6h -o json`,
+	Args: cobra.NoArgs,
+	RunE: runUsageForecast,
+}
+
+func init() {
+	usageForecastCmd.Flags().DurationVar(&forecastWindow, "window", defaultForecastWindow, "Burn rate window for local samples")
+	usageCmd.AddCommand(usageForecastCmd)
+}
+
+func runUsageForecast(cmd *cobra.Command, args []string) error {
+	if forecastWindow < minForecastSpan {
+		return &CLIError{Code: ErrCodeUsage, Message: fmt.Sprintf("--window must be at least %s", minForecastSpan)}
+	}
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), 2*api.DefaultTimeout)
+	defer cancel()
+
+	entry, err := currentUsage(ctx)
+	if err != nil {
+		return err
+	}
+	usage := entry.Usage
+	if !usage.Points.IsValid || !usage.TotalPoints.IsValid {
+		return &CLIError{Code: ErrCodeInternal, Message: "usage data is not available yet", Hint: "Try again once some usage has been recorded."}
+	}
+
+	now := time.Now()
+	forecast := forecastFromSamples(usage, loadUsageSamples(), forecastWindow, now)
+	if forecast == nil {
+		query := api.UsageHistoryQuery{From: now.AddDate(0, 0, -historyForecastDays), To: now, GroupBy: api.GroupByDay}
+		history, err := api.GetUsageHistory(ctx, query)
+		if err != nil {
+			return err
+		}
+		forecast = forecastFromHistory(usage, history, query, now)
+	}
+
+	return printResult(cmd, forecastResult{
+		ExhaustsAt:          forecast.ExhaustsAt,
+		PeriodEnd:           forecast.PeriodEnd,
+		Status:              "ok",
+		Source:              forecast.Source,
+		Points:              usage.Points.Value,
+		TotalPoints:         usage.TotalPoints.Value,
+		Remaining:           max(usage.TotalPoints.Value-usage.Points.Value, 0),
+		BurnRatePerHour:     forecast.BurnRatePerHour,
+		ExhaustsBeforeReset: forecast.ExhaustsBeforeReset,
+		window:              forecastWindow,
+	})
+}
+
+func (r forecastResult) printHuman(out io.Writer) error {
+	fmt.Fprintf(out, "Points:       %s / %s (%s remaining)\n", formatPoints(r.Points), formatPoints(r.TotalPoints), formatPoints(r.Remaining))
+
+	basis := fmt.Sprintf("last %s of local samples", formatDuration(r.window))
+	if r.Source == forecastSourceHistory {
+		basis = fmt.Sprintf("last %d days of usage history", historyForecastDays)
+	}
+	fmt.Fprintf(out, "Burn rate:    %s pts/h (%s)\n", formatPoints(r.BurnRatePerHour), basis)
+
+	if r.ExhaustsAt == nil {
+		fmt.Fprintln(out, "Runs out:     not at the current rate")
+	} else {
+		fmt.Fprintf(out, "Runs out:     in %s (%s)\n", formatDuration(time.Until(*r.ExhaustsAt)), r.ExhaustsAt.Local().Format("Mon Jan 2 15:04"))
+	}
+
+	if r.PeriodEnd != nil {
+		fmt.Fprintf(out, "Period ends:  %s\n", r.PeriodEnd.Local().Format("Mon Jan 2 15:04"))
+		if r.ExhaustsBeforeReset {
+			fmt.Fprintln(out, "\n⚠ At this rate the allowance runs out before the period resets.")
+		}
+	}
+	return nil
+}
+
+// loadUsageSamples returns the samples stored for the selected organization, oldest first
+func loadUsageSamples() []usageSample {
+	var stored usageSamples
+	if err := cache.Load(usageSamplesName, &stored); err != nil {
+		return nil
+	}
+	if stored.OrgID != auth.SelectedOrgID() {
+		return nil
+	}
+	return stored.Samples
+}
+
+// recordUsageSample appends a sample at most every sampleInterval. A drop in
+// points means a new billing period started, so older samples are discarded.
+func recordUsageSample(usage *api.UsageInfo, now time.Time) {
+	if !usage.Points.IsValid {
+		return
+	}
+
+	unlock, ok, err := cache.TryLock(usageSamplesName, sampleLockTimeout)
+	if err != nil || !ok {
+		return
+	}
+	defer unlock()
+
+	samples := loadUsageSamples()
+	if n := len(samples); n > 0 {
+		last := samples[n-1]
+		if usage.Points.Value < last.Points {
+			samples = nil
+		} else if now.Sub(last.Time) < sampleInterval {
+			return
+		}
+	}
+
+	kept := samples[:0]
+	for _, s := range samples {
+		if now.Sub(s.Time) <= sampleRetention {
+			kept = append(kept, s)
+		}
+	}
+	kept = append(kept, usageSample{Time: now, Points: usage.Points.Value})
+
+	if err := cache.Save(usageSamplesName, usageSamples{OrgID: auth.SelectedOrgID(), Samples: kept}); err != nil {
+		debug.Printf("Failed to save usage samples: %v\n", err)
+	}
+}
+
+// forecastFromSamples computes the burn rate over the samples within window.
+// It returns nil when the samples span less than minForecastSpan.
+func forecastFromSamples(usage *api.UsageInfo, samples []usageSample, window time.Duration, now time.Time) *usageForecast {
+	var first *usageSample
+	for i := range samples {
+		if now.Sub(samples[i].Time) <= window {
+			first = &samples[i]
+			break
+		}
+	}
+	if first == nil || !usage.Points.IsValid {
+		return nil
+	}
+
+	span := now.Sub(first.Time)
+	if span < minForecastSpan {
+		return nil
+	}
+	rate := max(usage.Points.Value-first.Points, 0) / span.Hours()
+	return newUsageForecast(usage, rate, forecastSourceSamples, now)
+}
+
+// forecastFromHistory computes the average hourly burn rate over a usage history range
+func forecastFromHistory(usage *api.UsageInfo, history *api.UsageHistory, query api.UsageHistoryQuery, now time.Time) *usageForecast {
+	total := history.TotalPoints
+	if total == 0 {
+		for _, e := range history.Entries {
+			total += e.Points
+		}
+	}
+	rate := 0.0
+	if hours := query.To.Sub(query.From).Hours(); hours > 0 {
+		rate = total / hours
+	}
+	return newUsageForecast(usage, rate, forecastSourceHistory, now)
+}
+
+func newUsageForecast(usage *api.UsageInfo, rate float64, source string, now time.Time) *usageForecast {
+	forecast := &usageForecast{
+		Source:          source,
+		BurnRatePerHour: math.Round(rate*10) / 10,
+		PeriodEnd:       parsePeriodEnd(usage.PeriodEnd),
+	}
+
+	if rate > 0 && usage.Points.IsValid && usage.TotalPoints.IsValid {
+		remaining := max(usage.TotalPoints.Value-usage.Points.Value, 0)
+		// Hours stay a float until they are known to fit a time.Duration
+		if hours := remaining / rate; hours < maxForecastHours {
+			exhaustsAt := now.Add(time.Duration(hours * float64(time.Hour))).Truncate(time.Second)
+			forecast.ExhaustsAt = &exhaustsAt
+			forecast.ExhaustsBeforeReset = forecast.PeriodEnd != nil && exhaustsAt.Before(*forecast.PeriodEnd)
+		}
+	}
+	return forecast
+}
+
+// parsePeriodEnd parses the API's period end as RFC 3339 or a plain date
+func parsePeriodEnd(s string) *time.Time {
+	for _, layout := range []string{time.RFC3339, "2006-01-02"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return &t
+		}
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package cli
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/costa-app/costa-cli/internal/api"
+)
+
+func usageInfo(points, total float64, periodEnd string) *api.UsageInfo {
+	return &api.UsageInfo{
+		Points:      api.FlexibleFloat{Value: points, IsValid: true},
+		TotalPoints: api.FlexibleFloat{Value: total, IsValid: true},
+		PeriodEnd:   periodEnd,
+	}
+}
+
+func TestRecordUsageSample(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	start := time.Now()
+
+	recordUsageSample(usageInfo(10, 100, ""), start)
+	recordUsageSample(usageInfo(11, 100, ""), start.Add(time.Minute)) // too soon
+	recordUsageSample(usageInfo(20, 100, ""), start.Add(10*time.Minute))
+	if got := len(loadUsageSamples()); got != 2 {
+		t.Fatalf("expected 2 samples, got %d", got)
+	}
+
+	// A drop in points starts a new billing period
+	recordUsageSample(usageInfo(1, 100, ""), start.Add(11*time.Minute))
+	samples := loadUsageSamples()
+	if len(samples) != 1 || samples[0].Points != 1 {
+		t.Errorf("expected samples to restart after a reset, got %+v", samples)
+	}
+}
+
+func TestForecastFromSamples(t *testing.T) {
+	now := time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)
+	samples := []usageSample{
+		{Time: now.Add(-48 * time.Hour), Points: 0}, // outside the window
+		{Time: now.Add(-4 * time.Hour), Points: 200},
+		{Time: now.Add(-2 * time.Hour), Points: 300},
+	}
+
+	forecast := forecastFromSamples(usageInfo(400, 1000, "2025-01-11"), samples, 24*time.Hour, now)
+	if forecast == nil {
+		t.Fatal("expected a forecast")
+	}
+	if forecast.BurnRatePerHour != 50 {
+		t.Errorf("expected 50 pts/h, got %v", forecast.BurnRatePerHour)
+	}
+	if want := now.Add(12 * time.Hour); forecast.ExhaustsAt == nil || !forecast.ExhaustsAt.Equal(want) {
+		t.Errorf("expected exhaustion at %v, got %v", want, forecast.ExhaustsAt)
+	}
+	if forecast.ExhaustsBeforeReset {
+		t.Error("expected allowance to last until the period resets")
+	}
+
+	// Not enough history for a rate
+	if f := forecastFromSamples(usageInfo(400, 1000, ""), samples[2:], time.Hour, now.Add(-time.Hour-50*time.Minute)); f != nil {
+		t.Errorf("expected no forecast from a short sample span, got %+v", f)
+	}
+
+	// Flat usage never runs out
+	flat := forecastFromSamples(usageInfo(300, 1000, ""), samples[2:], 24*time.Hour, now)
+	if flat == nil || flat.ExhaustsAt != nil {
+		t.Errorf("expected no exhaustion for flat usage, got %+v", flat)
+	}
+}
+
+func TestNewUsageForecast_TinyRate(t *testing.T) {
+	now := time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	// 500000 points at 0.1 pts/h is far beyond what a time.Duration can hold
+	forecast := newUsageForecast(usageInfo(0, 500000, "2025-01-31"), 0.1, forecastSourceSamples, now)
+	if forecast.ExhaustsAt != nil || forecast.ExhaustsBeforeReset {
+		t.Errorf("expected no exhaustion at a tiny burn rate, got %+v", forecast)
+	}
+}
+
+func TestUsageForecast_HistoryFallback(t *testing.T) {
+	setupUsageServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/usage":
+			_, _ = w.Write([]byte(`{"points": 832, "total_points": "1000", "period_end": "2099-01-01T00:00:00Z"}`))
+		case "/api/v1/usage/history":
+			_, _ = w.Write([]byte(`{"entries": [{"key": "2025-01-01", "points": 840}], "total_points": 840}`))
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	output, err := runUsageCommand(t, "forecast", "-o", "json")
+	if err != nil {
+		t.Fatalf("Command failed: %v\noutput: %s", err, output)
+	}
+
+	var result map[string]any
+	if err := json.Unmarshal([]byte(output), &result); err != nil {
+		t.Fatalf("failed to parse JSON output: %v\noutput: %s", err, output)
+	}
+	if result["source"] != forecastSourceHistory {
+		t.Errorf("expected history source, got %v", result["source"])
+	}
+	// 840 points over 7 days is 5 pts/h; 168 remaining points last 33.6h
+	if result["burn_rate_per_hour"] != 5.0 || result["remaining"] != 168.0 {
+		t.Errorf("unexpected forecast: %v", result)
+	}
+	if result["exhausts_before_reset"] != true {
+		t.Errorf("expected exhaustion before reset, got %v", result["exhausts_before_reset"])
+	}
+}
@@ -6,7 +6,6 @@ import (
 	"crypto/sha256"
 	_ "embed"
 	"encoding/base64"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -21,6 +20,7 @@ import (
 	"golang.org/x/oauth2"
 
 	"github.com/costa-app/costa-cli/internal/auth"
+	"github.com/costa-app/costa-cli/internal/httpclient"
 )
 
 //go:embed login_success.html
@@ -35,6 +35,25 @@ var (
 	pollInterval     = 500 * time.Millisecond
 )
 
+// loginResult is the machine-readable output of 'costa login'
+type loginResult struct {
+	Status         string `json:"status"`
+	AuthURL        string `json:"auth_url,omitempty"`
+	RedirectURI    string `json:"redirect_uri,omitempty"`
+	Message        string `json:"message,omitempty"`
+	TimeoutSeconds int    `json:"timeout_seconds,omitempty"`
+	LoggedIn       bool   `json:"logged_in"`
+}
+
+func (r loginResult) printHuman(w io.Writer) error {
+	if r.Status == "already_logged_in" {
+		fmt.Fprintln(w, "Already logged in. Use 'costa logout' to logout first.")
+		return nil
+	}
+	fmt.Fprintf(w, "Visit %s to finish logging in.\n", r.AuthURL)
+	return nil
+}
+
 var loginCmd = &cobra.Command{
 	Use:   "login",
 	Short: "Authenticate with Costa",
@@ -47,18 +66,11 @@ var loginCmd = &cobra.Command{
 
 		// If we're already logged in, exit early
 		if auth.IsLoggedIn() {
-			if loginFormat == "json" {
-				return writeJSON(cmd, map[string]any{
-					"status":    "already_logged_in",
-					"logged_in": true,
-				})
-			}
-			fmt.Fprintln(cmd.OutOrStdout(), "Already logged in. Use 'costa logout' to logout first.")
-			return nil
+			return printResult(cmd, loginResult{Status: "already_logged_in", LoggedIn: true})
 		}
 
-		// JSON mode: spawn fresh background server with this invocation's PKCE params
-		if loginFormat == "json" {
+		// Machine-readable mode: spawn fresh background server with this invocation's PKCE params
+		if outputFormat(cmd) != "" {
 			// Generate PKCE parameters for this session
 			state, err := generateRandomState()
 			if err != nil {
@@ -110,12 +122,12 @@ var loginCmd = &cobra.Command{
 			)
 
 			// Return immediately with auth URL
-			return writeJSON(cmd, map[string]any{
-				"status":          "waiting_for_user",
-				"auth_url":        authURL,
-				"timeout_seconds": int(loginWaitTimeout / time.Second),
-				"redirect_uri":    auth.GetRedirectURL(),
-				"message":         "OAuth server started in background, poll 'costa status --format json' to detect completion",
+			return printResult(cmd, loginResult{
+				Status:         "waiting_for_user",
+				AuthURL:        authURL,
+				TimeoutSeconds: int(loginWaitTimeout / time.Second),
+				RedirectURI:    auth.GetRedirectURL(),
+				Message:        "OAuth server started in background, poll 'costa status --format json' to detect completion",
 			})
 		}
 
@@ -136,7 +148,7 @@ func runOAuthServer(_ *cobra.Command) error {
 	// Listen on the callback port
 	ln, err := net.Listen("tcp", ":"+auth.RedirectPort)
 	if err != nil {
-		return fmt.Errorf("failed to bind callback port: %w", err)
+		return callbackPortError(err)
 	}
 	defer func() { _ = ln.Close() }()
 
@@ -209,7 +221,11 @@ func runOAuthServer(_ *cobra.Command) error {
 	_ = server.Shutdown(context.Background())
 
 	// Exchange authorization code for token with PKCE verifier
-	token, err := config.Exchange(context.Background(), code,
+	exchangeCtx, err := httpclient.Context(context.Background())
+	if err != nil {
+		return err
+	}
+	token, err := config.Exchange(exchangeCtx, code,
 		oauth2.SetAuthURLParam("code_verifier", loginVerifier),
 	)
 	if err != nil {
@@ -342,7 +358,7 @@ func runInteractiveLogin(cmd *cobra.Command) error {
 			fmt.Fprintln(cmd.OutOrStdout(), "Successfully logged in!")
 			return nil
 		}
-		return fmt.Errorf("failed to bind callback port: %w", err)
+		return callbackPortError(err)
 	}
 
 	// Start server in goroutine
@@ -384,7 +400,11 @@ func runInteractiveLogin(cmd *cobra.Command) error {
 	_ = server.Shutdown(ctx)
 
 	// Exchange authorization code for token with PKCE verifier
-	token, err := config.Exchange(context.Background(), code,
+	exchangeCtx, err := httpclient.Context(context.Background())
+	if err != nil {
+		return err
+	}
+	token, err := config.Exchange(exchangeCtx, code,
 		oauth2.SetAuthURLParam("code_verifier", verifier),
 	)
 	if err != nil {
@@ -426,14 +446,17 @@ func runInteractiveLogin(cmd *cobra.Command) error {
 	return nil
 }
 
-// writeJSON prints a single-line JSON object to stdout
-func writeJSON(cmd *cobra.Command, m map[string]any) error {
-	data, err := json.Marshal(m)
-	if err != nil {
-		return err
+// callbackPortError wraps a failure to bind the OAuth callback port
+func callbackPortError(err error) error {
+	if errors.Is(err, syscall.EADDRINUSE) {
+		return &CLIError{
+			Code:    ErrCodeConflict,
+			Message: fmt.Sprintf("failed to bind callback port %s: address already in use", auth.RedirectPort),
+			Hint:    "Another process is using the port; close it or wait for the pending login to finish.",
+			Err:     err,
+		}
 	}
-	fmt.Fprintln(cmd.OutOrStdout(), string(data))
-	return nil
+	return fmt.Errorf("failed to bind callback port: %w", err)
 }
 
 // waitUntilLoggedIn polls until auth.IsLoggedIn() returns true or context is done
@@ -482,7 +505,7 @@ func openBrowser(url string) error {
 }
 
 func init() {
-	loginCmd.Flags().StringVar(&loginFormat, "format", "", "Output format (json)")
+	loginCmd.Flags().StringVar(&loginFormat, "format", "", "Output format (json); alias for --output json")
 	loginCmd.Flags().BoolVar(&loginServerMode, "server-mode", false, "(internal) Run OAuth server in background mode")
 	loginCmd.Flags().StringVar(&loginState, "state", "", "(internal) PKCE state for server mode")
 	loginCmd.Flags().StringVar(&loginVerifier, "verifier", "", "(internal) PKCE verifier for server mode")
@@ -1,18 +1,35 @@
 package cli
 
 import (
-	"encoding/json"
 	"fmt"
+	"io"
 
 	"github.com/spf13/cobra"
 
 	"github.com/costa-app/costa-cli/internal/auth"
+	"github.com/costa-app/costa-cli/internal/cache"
+	"github.com/costa-app/costa-cli/internal/debug"
 )
 
 var (
 	logoutFormat string
 )
 
+// logoutResult is the output of 'costa logout'
+type logoutResult struct {
+	Status   string `json:"status"`
+	LoggedIn bool   `json:"logged_in"`
+}
+
+func (r logoutResult) printHuman(w io.Writer) error {
+	if r.Status == "not_logged_in" {
+		fmt.Fprintln(w, "Not currently logged in.")
+		return nil
+	}
+	fmt.Fprintln(w, "Successfully logged out!")
+	return nil
+}
+
 var logoutCmd = &cobra.Command{
 	Use:   "logout",
 	Short: "Logout from Costa",
@@ -20,48 +37,23 @@ var logoutCmd = &cobra.Command{
 	RunE: func(cmd *cobra.Command, args []string) error {
 		// Check if logged in
 		if !auth.IsLoggedIn() {
-			if logoutFormat == "json" {
-				return writeLogoutJSON(cmd, map[string]any{
-					"status":    "not_logged_in",
-					"logged_in": false,
-				})
-			}
-			fmt.Fprintln(cmd.OutOrStdout(), "Not currently logged in.")
-			return nil
+			return printResult(cmd, logoutResult{Status: "not_logged_in"})
 		}
 
 		// Delete token
 		if err := auth.DeleteToken(); err != nil {
-			if logoutFormat == "json" {
-				return writeLogoutJSON(cmd, map[string]any{
-					"status": "error",
-					"error":  err.Error(),
-				})
-			}
 			return fmt.Errorf("failed to logout: %w", err)
 		}
 
-		if logoutFormat == "json" {
-			return writeLogoutJSON(cmd, map[string]any{
-				"status":    "success",
-				"logged_in": false,
-			})
+		// Cached usage belongs to the account that just logged out
+		if err := cache.Remove(usageCacheName); err != nil {
+			debug.Printf("Failed to clear usage cache: %v\n", err)
 		}
-		fmt.Fprintln(cmd.OutOrStdout(), "Successfully logged out!")
-		return nil
-	},
-}
 
-// writeLogoutJSON prints a single-line JSON object to stdout
-func writeLogoutJSON(cmd *cobra.Command, m map[string]any) error {
-	data, err := json.Marshal(m)
-	if err != nil {
-		return err
-	}
-	fmt.Fprintln(cmd.OutOrStdout(), string(data))
-	return nil
+		return printResult(cmd, logoutResult{Status: "success"})
+	},
 }
 
 func init() {
-	logoutCmd.Flags().StringVar(&logoutFormat, "format", "", "Output format (json)")
+	logoutCmd.Flags().StringVar(&logoutFormat, "format", "", "Output format (json); alias for --output json")
 }
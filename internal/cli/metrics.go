@@ -0,0 +1,181 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/costa-app/costa-cli/internal/api"
+	"github.com/costa-app/costa-cli/internal/auth"
+)
+
+var (
+	metricsListen   string
+	metricsInterval time.Duration
+)
+
+var metricsCmd = &cobra.Command{
+	Use:   "metrics",
+	Short: "Export Costa usage metrics",
+	Long:  `Export Costa usage metrics for monitoring systems.`,
+}
+
+var metricsServeCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve usage metrics in Prometheus format",
+	Long: `Run a long-lived exporter that polls Costa usage and serves it on /metrics
+in the Prometheus text exposition format (0.0.4).
+
+Usage is read through the same cache as 'costa status', and the OAuth token
+is refreshed automatically while the exporter runs.`,
+	Example: `  costa metrics serve
+  costa metrics serve --listen 0.0.0.0:9464 --interval 1m`,
+	RunE: runMetricsServe,
+}
+
+func init() {
+	metricsServeCmd.Flags().StringVar(&metricsListen, "listen", "127.0.0.1:9464", "Address to serve /metrics on")
+	metricsServeCmd.Flags().DurationVar(&metricsInterval, "interval", 30*time.Second, "How often to poll Costa usage")
+	metricsCmd.AddCommand(metricsServeCmd)
+}
+
+// usageExporter polls Costa usage and renders the latest values as Prometheus metrics
+type usageExporter struct {
+	usage        *api.UsageInfo
+	lastSuccess  time.Time
+	mu           sync.Mutex
+	scrapeErrors int
+	// lastPollOK reports whether the most recent poll succeeded
+	lastPollOK bool
+}
+
+func runMetricsServe(cmd *cobra.Command, args []string) error {
+	if metricsInterval < time.Second {
+		return &CLIError{Code: ErrCodeUsage, Message: "--interval must be at least 1s"}
+	}
+
+	ln, err := net.Listen("tcp", metricsListen)
+	if err != nil {
+		if errors.Is(err, syscall.EADDRINUSE) {
+			return &CLIError{
+				Code:    ErrCodeConflict,
+				Message: fmt.Sprintf("failed to listen on %s: address already in use", metricsListen),
+				Hint:    "Stop the other exporter or choose a different --listen address.",
+				Err:     err,
+			}
+		}
+		return fmt.Errorf("failed to listen on %s: %w", metricsListen, err)
+	}
+
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	exporter := &usageExporter{}
+	exporter.poll(ctx)
+	go exporter.run(ctx, metricsInterval)
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", exporter)
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprintln(w, "Costa usage exporter. Metrics are served on /metrics.")
+	})
+
+	server := &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	fmt.Fprintf(cmd.ErrOrStderr(), "Serving Costa metrics on http://%s/metrics\n", ln.Addr())
+	if err := server.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// run polls usage every interval until ctx is done
+func (e *usageExporter) run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			e.poll(ctx)
+		}
+	}
+}
+
+// poll refreshes usage, serving a fresh cache entry written by another costa
+// process when there is one
+func (e *usageExporter) poll(ctx context.Context) {
+	entry, err := currentUsage(ctx)
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.lastPollOK = err == nil
+	if err != nil {
+		e.scrapeErrors++
+		return
+	}
+	e.usage = entry.Usage
+	e.lastSuccess = entry.FetchedAt
+}
+
+func (e *usageExporter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	e.writeMetrics(w, time.Now())
+}
+
+// writeMetrics writes the current state in the Prometheus text exposition format
+func (e *usageExporter) writeMetrics(w io.Writer, now time.Time) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	up := 0.0
+	if e.lastPollOK {
+		up = 1
+	}
+	writeMetric(w, "costa_up", "gauge", "Whether the last poll of the Costa usage API succeeded.", up)
+	writeMetric(w, "costa_scrape_errors_total", "counter", "Number of failed polls of the Costa usage API.", float64(e.scrapeErrors))
+
+	if e.usage != nil {
+		if e.usage.Points.IsValid {
+			writeMetric(w, "costa_points_used", "gauge", "Points used in the current billing period.", e.usage.Points.Value)
+		}
+		if e.usage.TotalPoints.IsValid {
+			writeMetric(w, "costa_points_total", "gauge", "Points available in the current billing period.", e.usage.TotalPoints.Value)
+		}
+		writeMetric(w, "costa_context_length", "gauge", "Context length reported by the Costa usage API.", e.usage.ContextLen)
+		writeMetric(w, "costa_last_success_timestamp_seconds", "gauge", "Unix time of the last successful usage fetch.", float64(e.lastSuccess.Unix()))
+	}
+
+	if expiry := auth.OAuthExpiry(); expiry != nil {
+		writeMetric(w, "costa_token_expiry_seconds", "gauge", "Seconds until the OAuth access token expires.", expiry.Sub(now).Seconds())
+	}
+}
+
+func writeMetric(w io.Writer, name, kind, help string, value float64) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n%s %s\n", name, help, name, kind, name, strconv.FormatFloat(value, 'f', -1, 64))
+}
@@ -0,0 +1,75 @@
+package cli
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestUsageExporter(t *testing.T) {
+	var fail atomic.Bool
+	setupUsageServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if fail.Load() {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"points": 250, "total_points": "1000", "context_length": 200000}`))
+	})
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	exporter := &usageExporter{}
+	fail.Store(true)
+	exporter.poll(context.Background())
+
+	rec := httptest.NewRecorder()
+	exporter.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body := rec.Body.String()
+	for _, want := range []string{"costa_up 0", "costa_scrape_errors_total 1", "# TYPE costa_scrape_errors_total counter"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected %q in metrics:\n%s", want, body)
+		}
+	}
+	if strings.Contains(body, "costa_points_used") {
+		t.Errorf("expected no usage metrics before a successful poll:\n%s", body)
+	}
+
+	fail.Store(false)
+	exporter.poll(context.Background())
+
+	rec = httptest.NewRecorder()
+	exporter.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body = rec.Body.String()
+	for _, want := range []string{
+		"costa_up 1",
+		"costa_scrape_errors_total 1",
+		"# TYPE costa_points_used gauge",
+		"costa_points_used 250",
+		"costa_points_total 1000",
+		"costa_context_length 200000",
+		"costa_token_expiry_seconds ",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected %q in metrics:\n%s", want, body)
+		}
+	}
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain") {
+		t.Errorf("unexpected content type %q", ct)
+	}
+
+	// A failed poll after a success marks the exporter down but keeps the last values
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	fail.Store(true)
+	exporter.poll(context.Background())
+
+	rec = httptest.NewRecorder()
+	exporter.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body = rec.Body.String()
+	for _, want := range []string{"costa_up 0", "costa_scrape_errors_total 2", "costa_points_used 250", "costa_last_success_timestamp_seconds "} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected %q in metrics:\n%s", want, body)
+		}
+	}
+}
@@ -0,0 +1,226 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/costa-app/costa-cli/internal/api"
+	"github.com/costa-app/costa-cli/internal/auth"
+	"github.com/costa-app/costa-cli/internal/cache"
+	"github.com/costa-app/costa-cli/internal/debug"
+	"github.com/costa-app/costa-cli/internal/integrations"
+)
+
+var (
+	orgUseClear  bool
+	orgUseNoSync bool
+)
+
+// orgEntry is one organization in 'costa org list'
+type orgEntry struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Slug    string `json:"slug,omitempty"`
+	Current bool   `json:"current"`
+}
+
+// orgListResult is the output of 'costa org list'
+type orgListResult struct {
+	Status        string     `json:"status"`
+	Organizations []orgEntry `json:"organizations"`
+}
+
+func (r orgListResult) table() ([]string, [][]string) {
+	headers := []string{"CURRENT", "ID", "NAME", "SLUG"}
+	var rows [][]string
+	for _, o := range r.Organizations {
+		current := ""
+		if o.Current {
+			current = "*"
+		}
+		rows = append(rows, []string{current, o.ID, o.Name, o.Slug})
+	}
+	return headers, rows
+}
+
+func (r orgListResult) printHuman(out io.Writer) error {
+	if len(r.Organizations) == 0 {
+		fmt.Fprintln(out, "No organizations found for this account.")
+		return nil
+	}
+	for _, o := range r.Organizations {
+		marker := " "
+		if o.Current {
+			marker = "*"
+		}
+		fmt.Fprintf(out, "%s %s\t%s\n", marker, formatOrg(&auth.Organization{ID: o.ID, Name: o.Name, Slug: o.Slug}), o.ID)
+	}
+	return nil
+}
+
+// orgUseResult is the output of 'costa org use'
+type orgUseResult struct {
+	// Org is nil when the selection was cleared
+	Org      *auth.Organization `json:"org"`
+	Status   string             `json:"status"`
+	Synced   []string           `json:"synced"`
+	Warnings []string           `json:"warnings,omitempty"`
+}
+
+func (r orgUseResult) printHuman(out io.Writer) error {
+	if r.Org == nil {
+		fmt.Fprintln(out, "✓ Using your account's default organization")
+	} else {
+		fmt.Fprintf(out, "✓ Switched to organization %s\n", formatOrg(r.Org))
+	}
+	for _, name := range r.Synced {
+		fmt.Fprintf(out, "✓ Updated %s with the new coding token\n", name)
+	}
+	for _, warning := range r.Warnings {
+		fmt.Fprintf(out, "⚠ %s\n", warning)
+	}
+	return nil
+}
+
+var orgCmd = &cobra.Command{
+	Use:   "org",
+	Short: "List and switch Costa organizations",
+	Long: `List the Costa organizations your account belongs to and choose which one
+coding tokens and usage are scoped to. The selection is stored per profile.`,
+}
+
+var orgListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List organizations you belong to",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, cancel := context.WithTimeout(cmd.Context(), api.DefaultTimeout)
+		defer cancel()
+
+		orgs, err := api.ListOrganizations(ctx)
+		if err != nil {
+			return err
+		}
+
+		result := orgListResult{Status: "ok", Organizations: []orgEntry{}}
+		selected := auth.SelectedOrg()
+		for _, o := range orgs {
+			result.Organizations = append(result.Organizations, orgEntry{
+				ID:      o.ID,
+				Name:    o.Name,
+				Slug:    o.Slug,
+				Current: selected != nil && selected.ID == o.ID,
+			})
+		}
+		return printResult(cmd, result)
+	},
+}
+
+var orgUseCmd = &cobra.Command{
+	Use:   "use <org>",
+	Short: "Scope coding tokens and usage to an organization",
+	Long: `Select the organization, by ID, slug or name, that coding tokens and usage
+are scoped to for the active profile. Integrations already configured for
+Costa at user scope, such as Claude Code and Codex, are rewritten with a
+coding token for the new organization unless --no-sync is given.`,
+	Example: `  costa org use acme
+  costa org use --clear`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if orgUseClear {
+			return cobra.NoArgs(cmd, args)
+		}
+		return cobra.ExactArgs(1)(cmd, args)
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+
+		var org *auth.Organization
+		if !orgUseClear {
+			listCtx, cancel := context.WithTimeout(ctx, api.DefaultTimeout)
+			defer cancel()
+			orgs, err := api.ListOrganizations(listCtx)
+			if err != nil {
+				return err
+			}
+			if org = findOrg(orgs, args[0]); org == nil {
+				return &CLIError{
+					Code:    ErrCodeUsage,
+					Message: fmt.Sprintf("unknown organization: %s", args[0]),
+					Hint:    "Run 'costa org list' to see the organizations you belong to.",
+				}
+			}
+		}
+
+		if err := auth.SelectOrg(org); err != nil {
+			return fmt.Errorf("failed to save organization: %w", err)
+		}
+
+		// Cached usage and samples were measured against the previous organization
+		for _, name := range []string{usageCacheName, usageSamplesName, sessionStateName} {
+			if err := cache.Remove(name); err != nil {
+				debug.Printf("Failed to clear %s cache: %v\n", name, err)
+			}
+		}
+
+		result := orgUseResult{Status: "ok", Org: org, Synced: []string{}}
+		if !orgUseNoSync {
+			result.Synced, result.Warnings = syncIntegrationTokens(ctx)
+		}
+		return printResult(cmd, result)
+	},
+}
+
+// findOrg matches an organization by exact ID, or by slug or name ignoring case
+func findOrg(orgs []auth.Organization, query string) *auth.Organization {
+	for i := range orgs {
+		if orgs[i].ID == query {
+			return &orgs[i]
+		}
+	}
+	for i := range orgs {
+		if strings.EqualFold(orgs[i].Slug, query) || strings.EqualFold(orgs[i].Name, query) {
+			return &orgs[i]
+		}
+	}
+	return nil
+}
+
+// syncIntegrationTokens rewrites the coding token in every integration already
+// configured for Costa. Failures are reported as warnings, not errors.
+func syncIntegrationTokens(ctx context.Context) ([]string, []string) {
+	synced := []string{}
+	var warnings []string
+	for _, reg := range integrations.All() {
+		if !reg.SupportsScope(integrations.ScopeUser) {
+			continue
+		}
+		integration := reg.New()
+		status, err := integration.Status(ctx, integrations.ScopeUser)
+		if err != nil || !status.IsCosta {
+			continue
+		}
+		_, err = integration.Apply(ctx, integrations.ApplyOpts{
+			Scope:            integrations.ScopeUser,
+			Force:            true,
+			RefreshTokenOnly: true,
+		})
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("Failed to update %s: %v", integration.Name(), err))
+			continue
+		}
+		synced = append(synced, integration.Name())
+	}
+	return synced, warnings
+}
+
+func init() {
+	orgUseCmd.Flags().BoolVar(&orgUseClear, "clear", false, "Go back to the account's default organization")
+	orgUseCmd.Flags().BoolVar(&orgUseNoSync, "no-sync", false, "Do not rewrite configured integrations with the new coding token")
+
+	orgCmd.AddCommand(orgListCmd)
+	orgCmd.AddCommand(orgUseCmd)
+}
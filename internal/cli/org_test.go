@@ -0,0 +1,163 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/costa-app/costa-cli/internal/auth"
+	"github.com/costa-app/costa-cli/internal/integrations"
+	"github.com/costa-app/costa-cli/internal/integrations/claudecode"
+)
+
+func runOrgCommand(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+
+	defer func() {
+		orgUseClear, orgUseNoSync = false, false
+		for _, cmd := range []*cobra.Command{orgListCmd, orgUseCmd} {
+			if f := cmd.Flags().Lookup(outputFlagName); f != nil {
+				_ = f.Value.Set("")
+			}
+		}
+	}()
+
+	var buf bytes.Buffer
+	testRoot := &cobra.Command{Use: "costa", SilenceErrors: true, SilenceUsage: true}
+	testRoot.PersistentFlags().StringP(outputFlagName, "o", "", outputFlagUsage)
+	testRoot.AddCommand(orgCmd)
+	testRoot.SetOut(&buf)
+	testRoot.SetErr(&buf)
+	testRoot.SetArgs(append([]string{"org"}, args...))
+
+	err := testRoot.Execute()
+	return buf.String(), err
+}
+
+// setupOrgServer serves two organizations and hands out a coding token per
+// organization, recording the organization header of every request
+func setupOrgServer(t *testing.T) *sync.Map {
+	t.Helper()
+
+	headers := &sync.Map{}
+	setupUsageServer(t, func(w http.ResponseWriter, r *http.Request) {
+		org := r.Header.Get(auth.OrgHeader)
+		headers.Store(r.URL.Path, org)
+		switch r.URL.Path {
+		case "/api/v1/organizations":
+			_, _ = w.Write([]byte(`{"organizations": [{"id": "1", "name": "Personal"}, {"id": "42", "name": "Acme Corp", "slug": "acme"}]}`))
+		case "/api/v1/tokens/coding_current":
+			_, _ = w.Write([]byte(`{"token": "coding-for-` + org + `", "expires_at": "2099-01-01T00:00:00Z"}`))
+		default:
+			_, _ = w.Write([]byte(`{"points": 1, "total_points": 10}`))
+		}
+	})
+	return headers
+}
+
+func TestOrgUse(t *testing.T) {
+	headers := setupOrgServer(t)
+	home := os.Getenv("HOME")
+	t.Setenv("XDG_CACHE_HOME", filepath.Join(home, ".cache"))
+
+	// Claude Code already configured with the account's default coding token
+	if _, err := claudecode.New().Apply(context.Background(), integrations.ApplyOpts{
+		Scope:         integrations.ScopeUser,
+		TokenOverride: "coding-default",
+		Force:         true,
+	}); err != nil {
+		t.Fatalf("Failed to configure Claude Code: %v", err)
+	}
+
+	output, err := runOrgCommand(t, "use", "ACME")
+	if err != nil {
+		t.Fatalf("Command failed: %v\n%s", err, output)
+	}
+	if !strings.Contains(output, "Switched to organization Acme Corp (acme)") || !strings.Contains(output, "Updated claude-code") {
+		t.Errorf("unexpected output:\n%s", output)
+	}
+
+	if org := auth.SelectedOrg(); org == nil || org.ID != "42" {
+		t.Fatalf("expected org 42 to be selected, got %+v", org)
+	}
+	if got, _ := headers.Load("/api/v1/tokens/coding_current"); got != "42" {
+		t.Errorf("expected coding token request scoped to org 42, got %q", got)
+	}
+
+	settings, err := os.ReadFile(filepath.Join(home, ".claude", "settings.json"))
+	if err != nil {
+		t.Fatalf("Failed to read settings: %v", err)
+	}
+	if !strings.Contains(string(settings), "coding-for-42") {
+		t.Errorf("expected Claude Code to use the org coding token, got:\n%s", settings)
+	}
+
+	// The selection is shown by list and status
+	output, err = runOrgCommand(t, "list", "-o", "json")
+	if err != nil {
+		t.Fatalf("Command failed: %v", err)
+	}
+	var list orgListResult
+	if err := json.Unmarshal([]byte(output), &list); err != nil {
+		t.Fatalf("failed to parse JSON output: %v\noutput: %s", err, output)
+	}
+	if len(list.Organizations) != 2 || list.Organizations[0].Current || !list.Organizations[1].Current {
+		t.Errorf("expected only Acme to be current, got %+v", list.Organizations)
+	}
+
+	output, err = executeStatusCommand("-o", "json")
+	if err != nil {
+		t.Fatalf("status failed: %v", err)
+	}
+	if !strings.Contains(output, `"org"`) || !strings.Contains(output, "Acme Corp") {
+		t.Errorf("expected status to show the organization:\n%s", output)
+	}
+	if got, _ := headers.Load("/api/v1/usage"); got != "42" {
+		t.Errorf("expected usage request scoped to org 42, got %q", got)
+	}
+
+	// Clearing goes back to the default organization
+	if _, err := runOrgCommand(t, "use", "--clear", "--no-sync"); err != nil {
+		t.Fatalf("Command failed: %v", err)
+	}
+	if org := auth.SelectedOrg(); org != nil {
+		t.Errorf("expected no org after --clear, got %+v", org)
+	}
+}
+
+func TestOrgUse_PerProfile(t *testing.T) {
+	setupOrgServer(t)
+
+	t.Setenv("COSTA_PROFILE", "work")
+	if _, err := runOrgCommand(t, "use", "42", "--no-sync"); err != nil {
+		t.Fatalf("Command failed: %v", err)
+	}
+	if org := auth.SelectedOrg(); org == nil || org.ID != "42" {
+		t.Errorf("expected org 42 for the work profile, got %+v", org)
+	}
+
+	t.Setenv("COSTA_PROFILE", "")
+	if org := auth.SelectedOrg(); org != nil {
+		t.Errorf("expected no org for the default profile, got %+v", org)
+	}
+}
+
+func TestOrgUse_Unknown(t *testing.T) {
+	setupOrgServer(t)
+
+	_, err := runOrgCommand(t, "use", "nope")
+	if ExitCode(err) != ExitUsage {
+		t.Errorf("expected usage exit code, got %d (%v)", ExitCode(err), err)
+	}
+	if org := auth.SelectedOrg(); org != nil {
+		t.Errorf("expected no org to be selected, got %+v", org)
+	}
+}
@@ -0,0 +1,215 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+
+	"github.com/pelletier/go-toml/v2"
+	"github.com/spf13/cobra"
+	"golang.org/x/oauth2"
+
+	"github.com/costa-app/costa-cli/internal/auth"
+	"github.com/costa-app/costa-cli/internal/config"
+)
+
+// Error codes used in the "error.code" field of JSON output
+const (
+	ErrCodeAuthRequired  = "auth_required"
+	ErrCodeNetwork       = "network"
+	ErrCodeConflict      = "conflict"
+	ErrCodeCanceled      = "canceled"
+	ErrCodeConfig        = "config"
+	ErrCodeUsage         = "usage"
+	ErrCodeQuotaWarning  = "quota_warning"
+	ErrCodeQuotaExceeded = "quota_exceeded"
+	ErrCodeInternal      = "internal"
+)
+
+// Process exit codes, one per error class
+const (
+	ExitOK            = 0
+	ExitError         = 1
+	ExitUsage         = 2
+	ExitAuthRequired  = 3
+	ExitNetwork       = 4
+	ExitConflict      = 5
+	ExitConfig        = 6
+	ExitQuotaWarning  = 7
+	ExitQuotaExceeded = 8
+	ExitCanceled      = 130
+)
+
+// errCanceled is returned when the user declines a confirmation prompt
+var errCanceled = &CLIError{Code: ErrCodeCanceled, Message: "canceled by user"}
+
+// CLIError is an error with a stable machine-readable code and an optional hint
+type CLIError struct {
+	Err     error
+	Code    string
+	Message string
+	Hint    string
+	// resultPrinted means the command already wrote its machine-readable
+	// result, so only the exit code is still needed
+	resultPrinted bool
+}
+
+func (e *CLIError) Error() string {
+	if e.Message != "" {
+		return e.Message
+	}
+	if e.Err != nil {
+		return e.Err.Error()
+	}
+	return e.Code
+}
+
+func (e *CLIError) Unwrap() error {
+	return e.Err
+}
+
+// ExitCode returns the documented process exit code for the error class
+func (e *CLIError) ExitCode() int {
+	switch e.Code {
+	case ErrCodeAuthRequired:
+		return ExitAuthRequired
+	case ErrCodeNetwork:
+		return ExitNetwork
+	case ErrCodeConflict:
+		return ExitConflict
+	case ErrCodeCanceled:
+		return ExitCanceled
+	case ErrCodeConfig:
+		return ExitConfig
+	case ErrCodeUsage:
+		return ExitUsage
+	case ErrCodeQuotaWarning:
+		return ExitQuotaWarning
+	case ErrCodeQuotaExceeded:
+		return ExitQuotaExceeded
+	default:
+		return ExitError
+	}
+}
+
+// errorBody is the "error" object of the JSON envelope
+type errorBody struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Hint    string `json:"hint,omitempty"`
+}
+
+// errorEnvelope is printed for every failed JSON or YAML invocation
+type errorEnvelope struct {
+	Error  errorBody `json:"error"`
+	Status string    `json:"status"`
+}
+
+// newErrorBody converts an error into the JSON "error" object
+func newErrorBody(err error) errorBody {
+	ce := classifyError(err)
+	return errorBody{Code: ce.Code, Message: ce.Error(), Hint: ce.Hint}
+}
+
+// classifyError maps an arbitrary error onto a CLIError with a stable code
+func classifyError(err error) *CLIError {
+	var ce *CLIError
+	if errors.As(err, &ce) {
+		return ce
+	}
+
+	var (
+		cfgErr      *config.Error
+		jsonErr     *json.SyntaxError
+		tomlErr     *toml.DecodeError
+		retrieveErr *oauth2.RetrieveError
+		urlErr      *url.Error
+		netErr      net.Error
+	)
+
+	switch {
+	case errors.As(err, &retrieveErr):
+		return classifyRetrieveError(err, retrieveErr)
+	case errors.Is(err, auth.ErrLoginRequired):
+		return &CLIError{Code: ErrCodeAuthRequired, Err: err, Hint: "Run 'costa login' to authenticate."}
+	case errors.As(err, &cfgErr), errors.As(err, &jsonErr), errors.As(err, &tomlErr):
+		return &CLIError{Code: ErrCodeConfig, Err: err, Hint: "Fix or remove the invalid configuration and try again."}
+	case errors.As(err, &urlErr), errors.As(err, &netErr):
+		return &CLIError{Code: ErrCodeNetwork, Err: err, Hint: "Check your network connection and proxy settings (HTTPS_PROXY, COSTA_CA_BUNDLE)."}
+	default:
+		return &CLIError{Code: ErrCodeInternal, Err: err}
+	}
+}
+
+// classifyRetrieveError maps a failed token endpoint response. Only a rejected
+// grant means the user has to log in again; server errors and rate limits are
+// worth retrying.
+func classifyRetrieveError(err error, retrieveErr *oauth2.RetrieveError) *CLIError {
+	status := 0
+	if retrieveErr.Response != nil {
+		status = retrieveErr.Response.StatusCode
+	}
+	switch {
+	case status >= 400 && status < 500 && retrieveErr.ErrorCode == "invalid_grant":
+		return &CLIError{Code: ErrCodeAuthRequired, Err: err, Hint: "Run 'costa login' to authenticate."}
+	case status >= 500, status == http.StatusTooManyRequests:
+		return &CLIError{Code: ErrCodeNetwork, Err: err, Hint: "The Costa login service is unavailable; try again later."}
+	default:
+		return &CLIError{Code: ErrCodeInternal, Err: err}
+	}
+}
+
+// ExitCode returns the process exit code for an error returned by Execute
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+	return classifyError(err).ExitCode()
+}
+
+// reportError prints a failed command's error in the format the command was asked for
+func reportError(cmd *cobra.Command, err error) {
+	ce := classifyError(err)
+
+	switch outputFormat(cmd) {
+	case outputJSON:
+		if ce.resultPrinted {
+			return
+		}
+		_ = writeJSON(cmd.OutOrStdout(), errorEnvelope{Status: "error", Error: newErrorBody(err)})
+		return
+	case outputYAML:
+		if ce.resultPrinted {
+			return
+		}
+		_ = writeYAML(cmd.OutOrStdout(), errorEnvelope{Status: "error", Error: newErrorBody(err)})
+		return
+	}
+
+	// The canceled message has already been shown next to the prompt
+	if ce.Code == ErrCodeCanceled {
+		return
+	}
+	label := "Error"
+	if ce.Code == ErrCodeQuotaWarning {
+		label = "Warning"
+	}
+	fmt.Fprintf(cmd.ErrOrStderr(), "%s: %s\n", label, ce.Error())
+	if ce.Hint != "" {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Hint: %s\n", ce.Hint)
+	}
+}
+
+// writeJSON prints a value as a single-line JSON object
+func writeJSON(w io.Writer, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(w, string(data))
+	return nil
+}
@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/oauth2"
+
+	"github.com/costa-app/costa-cli/internal/auth"
+	"github.com/costa-app/costa-cli/internal/config"
+)
+
+func TestExitCode_ErrorClasses(t *testing.T) {
+	tests := []struct {
+		err      error
+		name     string
+		expected int
+	}{
+		{name: "nil", err: nil, expected: ExitOK},
+		{name: "auth required", err: fmt.Errorf("no OAuth token found: %w", auth.ErrLoginRequired), expected: ExitAuthRequired},
+		{name: "rejected grant", err: fmt.Errorf("refresh: %w", &oauth2.RetrieveError{Response: &http.Response{StatusCode: http.StatusBadRequest}, ErrorCode: "invalid_grant"}), expected: ExitAuthRequired},
+		{name: "token endpoint down", err: &oauth2.RetrieveError{Response: &http.Response{StatusCode: http.StatusBadGateway}}, expected: ExitNetwork},
+		{name: "token endpoint rate limit", err: &oauth2.RetrieveError{Response: &http.Response{StatusCode: http.StatusTooManyRequests}}, expected: ExitNetwork},
+		{name: "invalid client", err: &oauth2.RetrieveError{Response: &http.Response{StatusCode: http.StatusUnauthorized}, ErrorCode: "invalid_client"}, expected: ExitError},
+		{name: "network", err: &url.Error{Op: "Get", URL: "https://ai.costa.app", Err: errors.New("connection refused")}, expected: ExitNetwork},
+		{name: "config", err: &config.Error{Desc: "failed to parse config"}, expected: ExitConfig},
+		{name: "canceled", err: errCanceled, expected: ExitCanceled},
+		{name: "conflict", err: &CLIError{Code: ErrCodeConflict}, expected: ExitConflict},
+		{name: "generic", err: errors.New("boom"), expected: ExitError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExitCode(tt.err); got != tt.expected {
+				t.Errorf("ExitCode(%v) = %d, want %d", tt.err, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestReportError_JSONEnvelope(t *testing.T) {
+	var buf bytes.Buffer
+
+	cmd := &cobra.Command{Use: "test"}
+	cmd.Flags().String("format", "", "")
+	_ = cmd.Flags().Set("format", "json")
+	cmd.SetOut(&buf)
+	cmd.SetErr(&buf)
+
+	reportError(cmd, fmt.Errorf("failed: %w", auth.ErrLoginRequired))
+
+	output := buf.String()
+	if strings.Count(output, "\n") != 1 {
+		t.Errorf("expected single-line JSON output, got:\n%s", output)
+	}
+
+	var result struct {
+		Error  errorBody `json:"error"`
+		Status string    `json:"status"`
+	}
+	if err := json.Unmarshal([]byte(strings.TrimSpace(output)), &result); err != nil {
+		t.Fatalf("failed to parse JSON output: %v\noutput: %s", err, output)
+	}
+	if result.Status != "error" {
+		t.Errorf("expected status 'error', got %q", result.Status)
+	}
+	if result.Error.Code != ErrCodeAuthRequired {
+		t.Errorf("expected code %q, got %q", ErrCodeAuthRequired, result.Error.Code)
+	}
+	if result.Error.Hint == "" {
+		t.Error("expected hint to be set")
+	}
+}
+
+func TestReportError_Human(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+
+	cmd := &cobra.Command{Use: "test"}
+	cmd.SetOut(&stdout)
+	cmd.SetErr(&stderr)
+
+	reportError(cmd, errors.New("boom"))
+
+	if stdout.Len() != 0 {
+		t.Errorf("expected nothing on stdout, got: %s", stdout.String())
+	}
+	if !strings.Contains(stderr.String(), "Error: boom") {
+		t.Errorf("expected error on stderr, got: %s", stderr.String())
+	}
+}
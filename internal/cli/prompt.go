@@ -0,0 +1,158 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/costa-app/costa-cli/internal/auth"
+	"github.com/costa-app/costa-cli/internal/config"
+)
+
+// Shells and status bars supported by 'costa prompt'
+const (
+	promptBash     = "bash"
+	promptZsh      = "zsh"
+	promptFish     = "fish"
+	promptTmux     = "tmux"
+	promptStarship = "starship"
+)
+
+var promptShells = []string{promptBash, promptZsh, promptFish, promptTmux, promptStarship}
+
+var (
+	promptTimeout time.Duration
+	promptASCII   bool
+)
+
+var promptCmd = &cobra.Command{
+	Use:   "prompt <shell>",
+	Short: "Print a usage segment for shell prompts and tmux",
+	Long: `Print a compact usage segment for bash, zsh or fish prompts, tmux
+status-right or a starship custom module.
+
+The segment is read from the usage cache only, so a slow network never stalls
+the prompt: stale values are refreshed in the background, and nothing is
+printed until the first refresh finishes or when --timeout is exceeded.`,
+	Example: `  # bash: PS1='$(costa prompt bash) \w \$ '
+  # zsh:  setopt PROMPT_SUBST; PROMPT='$(costa prompt zsh) %~ %# '
+  # fish: in fish_prompt, costa prompt fish
+  # tmux: set -g status-right '#(costa prompt tmux)'
+  costa prompt starship`,
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: promptShells,
+	RunE:      runPrompt,
+}
+
+func init() {
+	promptCmd.Flags().DurationVar(&promptTimeout, "timeout", 150*time.Millisecond, "Print nothing if the segment takes longer than this")
+	promptCmd.Flags().BoolVar(&promptASCII, "ascii", false, "Use a plain-text label instead of an emoji icon")
+}
+
+func runPrompt(cmd *cobra.Command, args []string) error {
+	shell := args[0]
+	if !isPromptShell(shell) {
+		return &CLIError{
+			Code:    ErrCodeUsage,
+			Message: fmt.Sprintf("unsupported shell: %s", shell),
+			Hint:    "Use one of: " + strings.Join(promptShells, ", ") + ".",
+		}
+	}
+
+	segment := make(chan string, 1)
+	go func() {
+		segment <- promptSegment(shell, promptThresholds(), promptASCII)
+	}()
+
+	select {
+	case s := <-segment:
+		fmt.Fprint(cmd.OutOrStdout(), s)
+	case <-time.After(promptTimeout):
+		// A missing segment is better than a stalled prompt
+	}
+	return nil
+}
+
+func isPromptShell(shell string) bool {
+	for _, s := range promptShells {
+		if s == shell {
+			return true
+		}
+	}
+	return false
+}
+
+// promptThresholds returns the configured thresholds; prompts ignore config errors
+func promptThresholds() quotaThresholds {
+	cfg, err := config.Load()
+	if err != nil {
+		return quotaThresholds{}
+	}
+	thresholds, err := resolveThresholds("", "", cfg)
+	if err != nil {
+		return quotaThresholds{}
+	}
+	return thresholds
+}
+
+// promptSegment renders the cached usage for a shell, or "" when nothing is cached yet
+func promptSegment(shell string, thresholds quotaThresholds, ascii bool) string {
+	entry := loadUsageCache()
+	if (entry == nil || entry.stale()) && auth.HasStoredToken() {
+		startUsageRefresh()
+	}
+	if entry == nil {
+		return ""
+	}
+
+	usage := entry.Usage
+	level := quotaOK
+	if quota := thresholds.evaluate(usage); quota != nil {
+		level = quota.Level
+	}
+
+	icon := levelIcon(level)
+	if ascii {
+		icon = "costa"
+	}
+	text := fmt.Sprintf("%s %s/%s", icon, formatPointsValue(pointsValue(usage.Points)), formatPointsValue(pointsValue(usage.TotalPoints)))
+
+	color := levelColor(level)
+	if color == "" {
+		color = "cyan"
+	}
+	return colorPromptText(shell, color, text)
+}
+
+// colorPromptText colors text using the escaping each shell expects. color
+// is one of the status line's ansiColors, named the same in zsh and tmux.
+// Like colorize, it leaves the text uncolored when NO_COLOR is set.
+func colorPromptText(shell, color, text string) string {
+	switch shell {
+	case promptZsh:
+		text = strings.ReplaceAll(text, "%", "%%")
+	case promptTmux:
+		text = strings.ReplaceAll(text, "#", "##")
+	}
+	if os.Getenv("NO_COLOR") != "" {
+		return text
+	}
+
+	switch shell {
+	case promptBash:
+		// \001 and \002 mark non-printing bytes so readline measures the prompt correctly
+		return "\x01\x1b[" + ansiColors[color] + "m\x02" + text + "\x01\x1b[0m\x02"
+	case promptZsh:
+		return "%F{" + color + "}" + text + "%f"
+	case promptFish:
+		return "\x1b[" + ansiColors[color] + "m" + text + "\x1b[0m"
+	case promptTmux:
+		return "#[fg=" + color + "]" + text + "#[default]"
+	default:
+		// starship applies the module's own style
+		return text
+	}
+}
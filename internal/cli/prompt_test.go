@@ -0,0 +1,120 @@
+package cli
+
+import (
+	"bytes"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/costa-app/costa-cli/internal/api"
+	"github.com/costa-app/costa-cli/internal/auth"
+	"github.com/costa-app/costa-cli/internal/cache"
+)
+
+// seedUsageCache writes a usage cache entry of the given age and stubs background refreshes
+func seedUsageCache(t *testing.T, age time.Duration, points, total float64) *atomic.Int32 {
+	t.Helper()
+
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	entry := usageCacheEntry{
+		FetchedAt: time.Now().Add(-age),
+		Usage: &api.UsageInfo{
+			Points:      api.FlexibleFloat{Value: points, IsValid: true},
+			TotalPoints: api.FlexibleFloat{Value: total, IsValid: true},
+		},
+		BaseURL: auth.GetBaseURL(),
+	}
+	if err := cache.Save(usageCacheName, entry); err != nil {
+		t.Fatalf("Failed to seed cache: %v", err)
+	}
+
+	refreshes := &atomic.Int32{}
+	original := startUsageRefresh
+	startUsageRefresh = func() { refreshes.Add(1) }
+	t.Cleanup(func() { startUsageRefresh = original })
+	return refreshes
+}
+
+func TestPromptSegment(t *testing.T) {
+	seedUsageCache(t, 0, 12.5, 100)
+
+	tests := []struct {
+		shell    string
+		expected string
+	}{
+		{promptBash, "\x01\x1b[36m\x02💫 12.5/100\x01\x1b[0m\x02"},
+		{promptZsh, "%F{cyan}💫 12.5/100%f"},
+		{promptFish, "\x1b[36m💫 12.5/100\x1b[0m"},
+		{promptTmux, "#[fg=cyan]💫 12.5/100#[default]"},
+		{promptStarship, "💫 12.5/100"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.shell, func(t *testing.T) {
+			if got := promptSegment(tt.shell, quotaThresholds{}, false); got != tt.expected {
+				t.Errorf("promptSegment(%s) = %q, want %q", tt.shell, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestPromptSegment_ThresholdsAndEscaping(t *testing.T) {
+	seedUsageCache(t, 0, 96, 100)
+	failAt := 95.0
+
+	if got := promptSegment(promptTmux, quotaThresholds{FailAt: &failAt}, true); got != "#[fg=red]costa 96/100#[default]" {
+		t.Errorf("unexpected tmux segment: %q", got)
+	}
+	if got := colorPromptText(promptZsh, "red", "100%"); got != "%F{red}100%%%f" {
+		t.Errorf("expected %% to be escaped for zsh, got %q", got)
+	}
+	if got := colorPromptText(promptTmux, "red", "#1"); got != "#[fg=red]##1#[default]" {
+		t.Errorf("expected # to be escaped for tmux, got %q", got)
+	}
+}
+
+func TestPromptSegment_NoColor(t *testing.T) {
+	seedUsageCache(t, 0, 12.5, 100)
+	t.Setenv("NO_COLOR", "1")
+
+	if got := promptSegment(promptBash, quotaThresholds{}, true); got != "costa 12.5/100" {
+		t.Errorf("expected an uncolored bash segment, got %q", got)
+	}
+	if got := colorPromptText(promptZsh, "red", "100%"); got != "100%%" {
+		t.Errorf("expected %% to stay escaped for zsh, got %q", got)
+	}
+}
+
+func TestPromptSegment_CacheOnly(t *testing.T) {
+	refreshes := seedUsageCache(t, time.Minute, 1, 10)
+
+	// Stale values are still shown; the refresh happens in the background
+	if got := promptSegment(promptStarship, quotaThresholds{}, false); got != "💫 1/10" {
+		t.Errorf("expected stale cached segment, got %q", got)
+	}
+	if refreshes.Load() != 0 {
+		t.Errorf("expected no refresh without stored credentials, got %d", refreshes.Load())
+	}
+
+	if err := cache.Remove(usageCacheName); err != nil {
+		t.Fatalf("Failed to clear cache: %v", err)
+	}
+	if got := promptSegment(promptStarship, quotaThresholds{}, false); got != "" {
+		t.Errorf("expected empty segment without a cache, got %q", got)
+	}
+}
+
+func TestPrompt_UnsupportedShell(t *testing.T) {
+	var buf bytes.Buffer
+	testRoot := &cobra.Command{Use: "costa", SilenceErrors: true, SilenceUsage: true}
+	testRoot.AddCommand(promptCmd)
+	testRoot.SetOut(&buf)
+	testRoot.SetArgs([]string{"prompt", "powershell"})
+
+	if err := testRoot.Execute(); ExitCode(err) != ExitUsage {
+		t.Errorf("expected usage error, got %v", err)
+	}
+}
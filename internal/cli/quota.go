@@ -0,0 +1,129 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/costa-app/costa-cli/internal/api"
+	"github.com/costa-app/costa-cli/internal/config"
+)
+
+// Quota levels reported by 'costa status' and status line templates
+const (
+	quotaOK   = "ok"
+	quotaWarn = "warn"
+	quotaFail = "fail"
+)
+
+// quotaThresholds are usage percentages at which status warns or fails; nil disables a threshold
+type quotaThresholds struct {
+	WarnAt *float64
+	FailAt *float64
+}
+
+// quotaResult is the "quota" object of 'costa status' JSON output
+type quotaResult struct {
+	WarnAt      *float64 `json:"warn_at,omitempty"`
+	FailAt      *float64 `json:"fail_at,omitempty"`
+	Level       string   `json:"level"`
+	PercentUsed float64  `json:"percent_used"`
+}
+
+// resolveThresholds returns the --warn-at/--fail-at flags, falling back to the [thresholds] config
+func resolveThresholds(warnFlag, failFlag string, cfg *config.Config) (quotaThresholds, error) {
+	var q quotaThresholds
+
+	warn, warnSource := warnFlag, "--warn-at"
+	if warn == "" {
+		warn, warnSource = cfg.Thresholds.WarnAt, "thresholds.warn_at"
+	}
+	fail, failSource := failFlag, "--fail-at"
+	if fail == "" {
+		fail, failSource = cfg.Thresholds.FailAt, "thresholds.fail_at"
+	}
+
+	var err error
+	if q.WarnAt, err = parseThreshold(warn, warnSource); err != nil {
+		return q, err
+	}
+	if q.FailAt, err = parseThreshold(fail, failSource); err != nil {
+		return q, err
+	}
+	if q.WarnAt != nil && q.FailAt != nil && *q.WarnAt > *q.FailAt {
+		return q, &CLIError{
+			Code:    ErrCodeUsage,
+			Message: fmt.Sprintf("%s (%s) is above %s (%s)", warnSource, warn, failSource, fail),
+			Hint:    "The warning threshold must not be above the failure threshold.",
+		}
+	}
+	return q, nil
+}
+
+// parseThreshold parses a percentage such as "80%" or "80"; "" means no threshold
+func parseThreshold(s, source string) (*float64, error) {
+	if s == "" {
+		return nil, nil
+	}
+	v, err := strconv.ParseFloat(strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(s), "%")), 64)
+	if err != nil || v < 0 || v > 100 {
+		msg := fmt.Sprintf("invalid %s: %s", source, s)
+		if strings.HasPrefix(source, "--") {
+			return nil, &CLIError{Code: ErrCodeUsage, Message: msg, Hint: "Use a percentage between 0 and 100, e.g. 80%."}
+		}
+		return nil, &config.Error{Desc: msg}
+	}
+	return &v, nil
+}
+
+// enabled reports whether any threshold is set
+func (q quotaThresholds) enabled() bool {
+	return q.WarnAt != nil || q.FailAt != nil
+}
+
+// level returns the quota level for a usage percentage
+func (q quotaThresholds) level(percent float64) string {
+	switch {
+	case q.FailAt != nil && percent >= *q.FailAt:
+		return quotaFail
+	case q.WarnAt != nil && percent >= *q.WarnAt:
+		return quotaWarn
+	default:
+		return quotaOK
+	}
+}
+
+// evaluate returns the quota state for usage, or nil if the percentage used is unknown
+func (q quotaThresholds) evaluate(usage *api.UsageInfo) *quotaResult {
+	percent, ok := usage.PercentUsed()
+	if !ok {
+		return nil
+	}
+	return &quotaResult{
+		WarnAt:      q.WarnAt,
+		FailAt:      q.FailAt,
+		Level:       q.level(percent),
+		PercentUsed: percent,
+	}
+}
+
+// err returns the error that makes 'costa status' exit non-zero once a threshold is crossed
+func (r *quotaResult) err() *CLIError {
+	if r == nil {
+		return nil
+	}
+	switch r.Level {
+	case quotaFail:
+		return &CLIError{
+			Code:    ErrCodeQuotaExceeded,
+			Message: fmt.Sprintf("%s%% of points used (fail at %s%%)", formatPoints(r.PercentUsed), formatPoints(*r.FailAt)),
+		}
+	case quotaWarn:
+		return &CLIError{
+			Code:    ErrCodeQuotaWarning,
+			Message: fmt.Sprintf("%s%% of points used (warn at %s%%)", formatPoints(r.PercentUsed), formatPoints(*r.WarnAt)),
+		}
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,296 @@
+package cli
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+	"time"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// Output formats accepted by the global --output/-o flag
+const (
+	outputJSON       = "json"
+	outputYAML       = "yaml"
+	outputTable      = "table"
+	outputCSV        = "csv"
+	outputTemplate   = "go-template"
+	templatePrefix   = outputTemplate + "="
+	outputFlagName   = "output"
+	outputFlagUsage  = "Output format (json|yaml|table|csv|go-template=<template>)"
+	legacyFormatFlag = "format"
+)
+
+// humanPrinter is implemented by results with a custom human-readable form.
+// Results without it are printed as a table.
+type humanPrinter interface {
+	printHuman(w io.Writer) error
+}
+
+// tabler is implemented by results that want control over their table columns
+type tabler interface {
+	table() (headers []string, rows [][]string)
+}
+
+// outputFormat returns the requested machine-readable format, or "" for human output.
+// The global --output flag wins; a per-command --format json is kept as an alias.
+func outputFormat(cmd *cobra.Command) string {
+	if cmd == nil {
+		return ""
+	}
+	if f := cmd.Flags().Lookup(outputFlagName); f != nil && f.Value.String() != "" {
+		return f.Value.String()
+	}
+	if f := cmd.Flags().Lookup(legacyFormatFlag); f != nil && f.Value.String() == outputJSON {
+		return outputJSON
+	}
+	return ""
+}
+
+// validateOutputFormat rejects unknown --output values before a command runs
+func validateOutputFormat(format string) error {
+	switch {
+	case format == "", format == outputJSON, format == outputYAML, format == outputTable, format == outputCSV:
+		return nil
+	case strings.HasPrefix(format, templatePrefix):
+		if _, err := template.New("output").Funcs(templateFuncs()).Parse(strings.TrimPrefix(format, templatePrefix)); err != nil {
+			return &CLIError{Code: ErrCodeUsage, Message: fmt.Sprintf("invalid go-template: %v", err), Err: err}
+		}
+		return nil
+	default:
+		return &CLIError{
+			Code:    ErrCodeUsage,
+			Message: fmt.Sprintf("unknown output format: %s", format),
+			Hint:    "Use one of: json, yaml, table, csv, go-template=<template>.",
+		}
+	}
+}
+
+// printResult renders a command result in the requested output format
+func printResult(cmd *cobra.Command, result any) error {
+	format := outputFormat(cmd)
+	if err := validateOutputFormat(format); err != nil {
+		return err
+	}
+	return renderResult(cmd.OutOrStdout(), format, result)
+}
+
+func renderResult(w io.Writer, format string, result any) error {
+	switch {
+	case format == outputJSON:
+		return writeJSON(w, result)
+	case format == outputYAML:
+		return writeYAML(w, result)
+	case format == outputTable:
+		return writeTable(w, result)
+	case format == outputCSV:
+		return writeCSV(w, result)
+	case strings.HasPrefix(format, templatePrefix):
+		return writeTemplate(w, strings.TrimPrefix(format, templatePrefix), result)
+	default:
+		if hp, ok := result.(humanPrinter); ok {
+			return hp.printHuman(w)
+		}
+		return writeTable(w, result)
+	}
+}
+
+// toGeneric converts a result into maps/slices keyed by its JSON field names,
+// so YAML and templates see the same keys as JSON output
+func toGeneric(result any) (any, error) {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return nil, err
+	}
+	var generic any
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return nil, err
+	}
+	return generic, nil
+}
+
+func writeYAML(w io.Writer, result any) error {
+	generic, err := toGeneric(result)
+	if err != nil {
+		return err
+	}
+	data, err := yaml.Marshal(generic)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+func writeTemplate(w io.Writer, text string, result any) error {
+	tmpl, err := template.New("output").Funcs(templateFuncs()).Parse(text)
+	if err != nil {
+		return &CLIError{Code: ErrCodeUsage, Message: fmt.Sprintf("invalid go-template: %v", err), Err: err}
+	}
+	generic, err := toGeneric(result)
+	if err != nil {
+		return err
+	}
+	return tmpl.Execute(w, generic)
+}
+
+// templateFuncs returns the helper functions available to --output go-template
+func templateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"json": func(v any) (string, error) {
+			data, err := json.Marshal(v)
+			return string(data), err
+		},
+		"upper": strings.ToUpper,
+		"lower": strings.ToLower,
+		"join":  strings.Join,
+	}
+}
+
+func tableOf(result any) ([]string, [][]string) {
+	if t, ok := result.(tabler); ok {
+		return t.table()
+	}
+	return reflectTable(result)
+}
+
+func writeTable(w io.Writer, result any) error {
+	headers, rows := tableOf(result)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	if len(headers) > 0 {
+		fmt.Fprintln(tw, strings.Join(headers, "\t"))
+	}
+	for _, row := range rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+func writeCSV(w io.Writer, result any) error {
+	headers, rows := tableOf(result)
+
+	cw := csv.NewWriter(w)
+	if len(headers) > 0 {
+		if err := cw.Write(headers); err != nil {
+			return err
+		}
+	}
+	if err := cw.WriteAll(rows); err != nil {
+		return err
+	}
+	return cw.Error()
+}
+
+// reflectTable builds a table from a result: slices of structs become one row
+// per element, anything else becomes FIELD/VALUE rows with nested keys flattened
+func reflectTable(result any) ([]string, [][]string) {
+	v := reflect.Indirect(reflect.ValueOf(result))
+	if v.Kind() == reflect.Slice {
+		elemType := v.Type().Elem()
+		for elemType.Kind() == reflect.Ptr {
+			elemType = elemType.Elem()
+		}
+		if elemType.Kind() == reflect.Struct {
+			var headers []string
+			fields := jsonFields(elemType)
+			for _, f := range fields {
+				headers = append(headers, strings.ToUpper(f.name))
+			}
+			var rows [][]string
+			for i := 0; i < v.Len(); i++ {
+				elem := reflect.Indirect(v.Index(i))
+				var row []string
+				for _, f := range fields {
+					row = append(row, formatCell(elem.Field(f.index)))
+				}
+				rows = append(rows, row)
+			}
+			return headers, rows
+		}
+	}
+
+	var rows [][]string
+	flattenFields("", v, &rows)
+	return []string{"FIELD", "VALUE"}, rows
+}
+
+type jsonField struct {
+	name  string
+	index int
+}
+
+// jsonFields lists exported struct fields in declaration order with their JSON names
+func jsonFields(t reflect.Type) []jsonField {
+	var fields []jsonField
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		name := sf.Name
+		if tag := sf.Tag.Get("json"); tag != "" {
+			tagName := strings.Split(tag, ",")[0]
+			if tagName == "-" {
+				continue
+			}
+			if tagName != "" {
+				name = tagName
+			}
+		}
+		fields = append(fields, jsonField{name: name, index: i})
+	}
+	return fields
+}
+
+func flattenFields(prefix string, v reflect.Value, rows *[][]string) {
+	v = reflect.Indirect(v)
+	if !v.IsValid() {
+		return
+	}
+	if v.Kind() != reflect.Struct || v.Type() == reflect.TypeOf(time.Time{}) {
+		*rows = append(*rows, []string{prefix, formatCell(v)})
+		return
+	}
+	for _, f := range jsonFields(v.Type()) {
+		key := f.name
+		if prefix != "" {
+			key = prefix + "." + f.name
+		}
+		field := v.Field(f.index)
+		if (field.Kind() == reflect.Ptr || field.Kind() == reflect.Interface || field.Kind() == reflect.Slice) && field.IsNil() {
+			continue
+		}
+		flattenFields(key, field, rows)
+	}
+}
+
+func formatCell(v reflect.Value) string {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+	if t, ok := v.Interface().(time.Time); ok {
+		return t.Format(time.RFC3339)
+	}
+	if v.Kind() == reflect.Slice {
+		var parts []string
+		for i := 0; i < v.Len(); i++ {
+			parts = append(parts, formatCell(v.Index(i)))
+		}
+		return strings.Join(parts, ", ")
+	}
+	if v.Kind() == reflect.Float64 || v.Kind() == reflect.Float32 {
+		return formatPoints(v.Float())
+	}
+	return fmt.Sprint(v.Interface())
+}
@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/costa-app/costa-cli/pkg/version"
+)
+
+func TestRenderResult_Formats(t *testing.T) {
+	result := versionResult{Status: "ok", Version: "1.0.0", Commit: "abc123", Date: "2024-01-01"}
+
+	tests := []struct {
+		name     string
+		format   string
+		expected []string
+	}{
+		{name: "json", format: "json", expected: []string{`"version":"1.0.0"`, `"commit":"abc123"`}},
+		{name: "yaml", format: "yaml", expected: []string{"version: 1.0.0", "commit: abc123"}},
+		{name: "table", format: "table", expected: []string{"FIELD", "version  1.0.0", "commit   abc123"}},
+		{name: "go-template", format: "go-template={{.version}}/{{.commit}}", expected: []string{"1.0.0/abc123"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := renderResult(&buf, tt.format, result); err != nil {
+				t.Fatalf("renderResult failed: %v", err)
+			}
+			for _, want := range tt.expected {
+				if !strings.Contains(buf.String(), want) {
+					t.Errorf("expected output to contain %q, got:\n%s", want, buf.String())
+				}
+			}
+		})
+	}
+}
+
+func TestValidateOutputFormat(t *testing.T) {
+	if err := validateOutputFormat("xml"); ExitCode(err) != ExitUsage {
+		t.Errorf("expected usage error for unknown format, got: %v", err)
+	}
+	if err := validateOutputFormat("go-template={{.version"); ExitCode(err) != ExitUsage {
+		t.Errorf("expected usage error for invalid template, got: %v", err)
+	}
+	if err := validateOutputFormat("go-template={{.version}}"); err != nil {
+		t.Errorf("expected valid template, got: %v", err)
+	}
+}
+
+func TestOutputFlag_OverridesLegacyFormat(t *testing.T) {
+	origVersion := version.Version
+	version.Version = "1.0.0"
+	defer func() {
+		version.Version = origVersion
+		versionFormat = ""
+		// The merged persistent flag outlives testRoot, so reset it
+		if f := versionCmd.Flags().Lookup(outputFlagName); f != nil {
+			_ = f.Value.Set("")
+		}
+	}()
+
+	var buf bytes.Buffer
+
+	testRoot := &cobra.Command{Use: "costa"}
+	testRoot.PersistentFlags().StringP(outputFlagName, "o", "", outputFlagUsage)
+	testRoot.AddCommand(versionCmd)
+	testRoot.SetOut(&buf)
+	testRoot.SetErr(&buf)
+	testRoot.SetArgs([]string{"version", "--format", "json", "-o", "yaml"})
+
+	if err := testRoot.Execute(); err != nil {
+		t.Fatalf("version command failed: %v", err)
+	}
+
+	if !strings.Contains(buf.String(), "version: 1.0.0") {
+		t.Errorf("expected YAML output, got:\n%s", buf.String())
+	}
+}
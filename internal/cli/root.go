@@ -1,6 +1,8 @@
 package cli
 
 import (
+	"fmt"
+
 	"github.com/spf13/cobra"
 
 	"github.com/costa-app/costa-cli/pkg/version"
@@ -12,8 +14,14 @@ var rootCmd = &cobra.Command{
 	Long:  `Costa CLI helps you install plugins and manage your account.`,
 }
 
+// Execute runs the root command and reports any error in the format the
+// invoked command was asked for. Use ExitCode to map the error to an exit code.
 func Execute() error {
-	return rootCmd.Execute()
+	cmd, err := rootCmd.ExecuteC()
+	if err != nil {
+		reportError(cmd, err)
+	}
+	return err
 }
 
 func init() {
@@ -21,6 +29,20 @@ func init() {
 	rootCmd.Version = version.Get()
 	rootCmd.SetVersionTemplate("{{.Version}}\n")
 
+	// Global output format; per-command --format json remains an alias
+	rootCmd.PersistentFlags().StringP(outputFlagName, "o", "", outputFlagUsage)
+
+	// Errors are reported by Execute so JSON callers get the error envelope
+	rootCmd.SilenceErrors = true
+	rootCmd.SilenceUsage = true
+	rootCmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		return &CLIError{
+			Code: ErrCodeUsage,
+			Err:  err,
+			Hint: fmt.Sprintf("Run '%s --help' for usage.", cmd.CommandPath()),
+		}
+	})
+
 	// Disable command sorting, so we can control order
 	cobra.EnableCommandSorting = false
 
@@ -29,6 +51,12 @@ func init() {
 	rootCmd.AddCommand(logoutCmd)
 	rootCmd.AddCommand(tokenCmd)
 	rootCmd.AddCommand(statusCmd)
+	rootCmd.AddCommand(usageCmd)
+	rootCmd.AddCommand(metricsCmd)
+	rootCmd.AddCommand(promptCmd)
+	rootCmd.AddCommand(whoamiCmd)
+	rootCmd.AddCommand(orgCmd)
 	rootCmd.AddCommand(versionCmd)
 	rootCmd.AddCommand(setupCmd)
+	rootCmd.AddCommand(backupsCmd)
 }
@@ -0,0 +1,96 @@
+package cli
+
+import (
+	"time"
+
+	"github.com/costa-app/costa-cli/internal/auth"
+	"github.com/costa-app/costa-cli/internal/cache"
+	"github.com/costa-app/costa-cli/internal/debug"
+)
+
+const (
+	sessionStateName = "sessions"
+	// sessionStateTTL is how long an idle session's baseline is kept
+	sessionStateTTL = 7 * 24 * time.Hour
+	// sessionTouchInterval throttles last-seen updates so renders rarely write
+	sessionTouchInterval = time.Hour
+	sessionLockTimeout   = 5 * time.Second
+)
+
+// sessionBaseline is the account points total when a Claude Code session was first seen
+type sessionBaseline struct {
+	FirstSeen time.Time `json:"first_seen"`
+	LastSeen  time.Time `json:"last_seen"`
+	// OrgID is the organization whose points total the baseline is
+	OrgID  string  `json:"org_id,omitempty"`
+	Points float64 `json:"points"`
+}
+
+// sessionState is the on-disk store of session baselines, keyed by session id
+type sessionState struct {
+	Sessions map[string]sessionBaseline `json:"sessions"`
+}
+
+// sessionPointsDelta returns how many points were used since sessionID was first
+// seen, recording a baseline at the current total for new sessions. A stale total
+// may predate points spent just before the session started, so no baseline is
+// taken from it; ok is false until a fresh total has been seen.
+func sessionPointsDelta(sessionID string, points float64, stale bool, now time.Time) (delta float64, ok bool) {
+	var state sessionState
+	_ = cache.Load(sessionStateName, &state)
+
+	baseline, found := state.Sessions[sessionID]
+
+	// A baseline taken in another organization says nothing about this one's total
+	orgID := auth.SelectedOrgID()
+	sameOrg := found && baseline.OrgID == orgID
+
+	// Usage dropping below the baseline means a new billing period started
+	// mid-session; count from the reset rather than showing a negative delta
+	if sameOrg && points >= baseline.Points {
+		if now.Sub(baseline.LastSeen) >= sessionTouchInterval {
+			baseline.LastSeen = now
+			updateSessionState(sessionID, baseline, now)
+		}
+		return points - baseline.Points, true
+	}
+	if stale {
+		return 0, sameOrg
+	}
+
+	firstSeen := now
+	if found {
+		firstSeen = baseline.FirstSeen
+	}
+	updateSessionState(sessionID, sessionBaseline{FirstSeen: firstSeen, LastSeen: now, OrgID: orgID, Points: points}, now)
+	return 0, true
+}
+
+// updateSessionState stores a baseline and drops sessions idle for longer than sessionStateTTL.
+// Concurrent status line renders skip the write rather than wait for each other.
+func updateSessionState(sessionID string, baseline sessionBaseline, now time.Time) {
+	unlock, ok, err := cache.TryLock(sessionStateName, sessionLockTimeout)
+	if err != nil || !ok {
+		debug.Printf("Skipping session state update: lock unavailable (%v)\n", err)
+		return
+	}
+	defer unlock()
+
+	// Re-read under the lock so other sessions' updates are not lost
+	var state sessionState
+	_ = cache.Load(sessionStateName, &state)
+	if state.Sessions == nil {
+		state.Sessions = make(map[string]sessionBaseline)
+	}
+
+	for id, s := range state.Sessions {
+		if now.Sub(s.LastSeen) > sessionStateTTL {
+			delete(state.Sessions, id)
+		}
+	}
+	state.Sessions[sessionID] = baseline
+
+	if err := cache.Save(sessionStateName, state); err != nil {
+		debug.Printf("Failed to save session state: %v\n", err)
+	}
+}
@@ -0,0 +1,246 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/costa-app/costa-cli/internal/integrations"
+)
+
+// Per-tool outcomes reported by 'costa setup all'
+const (
+	setupToolNotInstalled = "not_installed"
+	setupToolUpToDate     = "already_configured"
+	setupToolPlanned      = "planned"
+	setupToolConfigured   = "configured"
+	setupToolFailed       = "failed"
+)
+
+var (
+	setupAllToken     string
+	setupAllBackupDir string
+	setupAllForce     bool
+	setupAllDryRun    bool
+	setupAllFormat    string
+)
+
+// setupAllTool is one tool's entry in the 'costa setup all' report
+type setupAllTool struct {
+	Error       *errorBody `json:"error,omitempty"`
+	Name        string     `json:"name"`
+	Title       string     `json:"title"`
+	Status      string     `json:"status"`
+	Version     string     `json:"version,omitempty"`
+	ConfigPath  string     `json:"config_path,omitempty"`
+	BackupPaths []string   `json:"backup_paths,omitempty"`
+	Committed   []string   `json:"committed,omitempty"`
+	Changes     []string   `json:"changes,omitempty"`
+	Warnings    []string   `json:"warnings,omitempty"`
+	// Plan describes each change, with tokens redacted
+	Plan []integrations.Change `json:"plan,omitempty"`
+	// Files holds a unified diff per file, with tokens redacted
+	Files []setupPlanFile `json:"files,omitempty"`
+	// result is the planned result, used to show diffs
+	result integrations.ApplyResult
+}
+
+// setupAllResult is the output of 'costa setup all'
+type setupAllResult struct {
+	Status string         `json:"status"`
+	Tools  []setupAllTool `json:"tools"`
+	DryRun bool           `json:"dry_run"`
+}
+
+func (r setupAllResult) table() ([]string, [][]string) {
+	headers := []string{"APP", "STATUS", "CHANGES", "CONFIG PATH"}
+	var rows [][]string
+	for _, tool := range r.Tools {
+		rows = append(rows, []string{tool.Name, tool.Status, fmt.Sprint(len(tool.Changes)), tool.ConfigPath})
+	}
+	return headers, rows
+}
+
+func (r setupAllResult) printHuman(out io.Writer) error {
+	if r.DryRun {
+		fmt.Fprintln(out, "\n🔍 Dry run - no changes made")
+	}
+
+	fmt.Fprintln(out, "\nSummary:")
+	for _, tool := range r.Tools {
+		var line string
+		switch tool.Status {
+		case setupToolNotInstalled:
+			line = "– not installed"
+		case setupToolUpToDate:
+			line = "✓ already configured"
+		case setupToolPlanned:
+			line = fmt.Sprintf("📝 %d change(s) planned", len(tool.Changes))
+		case setupToolConfigured:
+			line = "✅ configured"
+			if len(tool.BackupPaths) > 0 {
+				line += fmt.Sprintf(" (backup: %s)", strings.Join(tool.BackupPaths, ", "))
+			}
+		case setupToolFailed:
+			line = "✗ failed: " + tool.Error.Message
+		}
+		fmt.Fprintf(out, "  %-14s%s\n", tool.Title, line)
+	}
+	return nil
+}
+
+var setupAllCmd = &cobra.Command{
+	Use:   "all",
+	Short: "Configure every installed tool in one pass",
+	Long: `Detect which supported tools are installed, show the combined changes for
+all of them, ask for a single confirmation and apply them.
+
+Each tool is configured at its default scope and optional features that setup
+would otherwise ask about (such as the Claude Code status line) are included.`,
+	Args: cobra.NoArgs,
+	RunE: runSetupAll,
+}
+
+func init() {
+	setupAllCmd.Flags().StringVar(&setupAllToken, "token", "", "Use explicit token instead of fetching from Costa")
+	setupAllCmd.Flags().BoolVar(&setupAllForce, "force", false, "Skip confirmation prompt (auto-yes)")
+	setupAllCmd.Flags().BoolVar(&setupAllDryRun, "dry-run", false, "Show what would change without writing")
+	setupAllCmd.Flags().StringVar(&setupAllBackupDir, "backup-dir", "", "Custom backup directory for every tool")
+	setupAllCmd.Flags().StringVar(&setupAllFormat, "format", "", "Output format (json); alias for --output json")
+}
+
+// setupAllPlan is a tool with pending changes and the options that produced them
+type setupAllPlan struct {
+	reg  integrations.Registration
+	tool *setupAllTool
+	opts integrations.ApplyOpts
+}
+
+func runSetupAll(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	human := outputFormat(cmd) == ""
+
+	// Machine-readable output keeps stdout for the report
+	out := cmd.OutOrStdout()
+	if !human {
+		out = cmd.ErrOrStderr()
+	}
+
+	regs := integrations.All()
+	result := setupAllResult{Status: "ok", DryRun: setupAllDryRun, Tools: make([]setupAllTool, len(regs))}
+
+	// Phase 1: plan every installed tool
+	var plans []setupAllPlan
+	for i, reg := range regs {
+		tool := &result.Tools[i]
+		*tool = setupAllTool{Name: reg.Name, Title: reg.Title}
+
+		if reg.Detect != nil {
+			version, installed := reg.Detect()
+			if !installed {
+				tool.Status = setupToolNotInstalled
+				continue
+			}
+			tool.Version = version
+		}
+
+		opts := integrations.ApplyOpts{
+			Scope:         reg.Scopes[0],
+			TokenOverride: setupAllToken,
+			BackupDir:     setupAllBackupDir,
+			Force:         true,
+			DryRun:        true,
+		}
+		for _, prompt := range reg.Prompts {
+			if prompt.Skip == nil || !prompt.Skip(&opts) {
+				prompt.Accept(&opts)
+			}
+		}
+
+		plan, err := reg.New().Apply(ctx, opts)
+		tool.ConfigPath = plan.ConfigPath
+		switch {
+		case err != nil:
+			tool.Status = setupToolFailed
+			body := newErrorBody(err)
+			tool.Error = &body
+		case !plan.Changed:
+			tool.Status = setupToolUpToDate
+		default:
+			tool.Status = setupToolPlanned
+			tool.Changes = plan.UpdatedKeys
+			tool.Plan = plan.Changes
+			tool.Warnings = plan.Warnings
+			for _, d := range plan.Diffs {
+				tool.Files = append(tool.Files, setupPlanFile{Path: d.Path, Diff: unifiedDiff(d)})
+			}
+			tool.result = plan
+			plans = append(plans, setupAllPlan{reg: reg, tool: tool, opts: opts})
+		}
+	}
+
+	if human {
+		for _, plan := range plans {
+			fmt.Fprintf(out, "\n📝 %s (%s):\n", plan.reg.Title, plan.tool.ConfigPath)
+			printPlan(out, plan.tool.result)
+		}
+	}
+
+	// Phase 2: one confirmation, then apply everything
+	if len(plans) > 0 && !setupAllDryRun {
+		if !setupAllForce {
+			fmt.Fprintf(out, "\nApply changes to %d tool(s)? [Y/n]: ", len(plans))
+			response, _ := bufio.NewReader(cmd.InOrStdin()).ReadString('\n')
+			resp := strings.ToLower(strings.TrimSpace(response))
+			if resp == "n" || resp == "no" { // default YES
+				fmt.Fprintln(out, "Canceled.")
+				return errCanceled
+			}
+		}
+
+		for _, plan := range plans {
+			opts := plan.opts
+			opts.DryRun = false
+			applied, err := plan.reg.New().Apply(ctx, opts)
+			if err != nil {
+				plan.tool.Status = setupToolFailed
+				body := newErrorBody(err)
+				plan.tool.Error = &body
+				continue
+			}
+			plan.tool.Status = setupToolConfigured
+			plan.tool.BackupPaths = applied.BackupPaths
+			plan.tool.Committed = applied.Committed
+			plan.tool.Warnings = applied.Warnings
+			if human {
+				printWriteWarnings(out, plan.tool.result, applied)
+			}
+		}
+	}
+
+	var failed []string
+	for _, tool := range result.Tools {
+		if tool.Status == setupToolFailed {
+			failed = append(failed, tool.Name)
+		}
+	}
+	if len(failed) > 0 {
+		result.Status = "error"
+	}
+
+	if err := printResult(cmd, result); err != nil {
+		return err
+	}
+	if len(failed) > 0 {
+		return &CLIError{
+			Code:          ErrCodeInternal,
+			Message:       fmt.Sprintf("failed to configure %s", strings.Join(failed, ", ")),
+			Hint:          "Run 'costa setup <app>' for each failed tool to see details.",
+			resultPrinted: true,
+		}
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+// installFakeTools puts executables printing a version on an otherwise empty PATH
+func installFakeTools(t *testing.T, names ...string) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake tools are shell scripts")
+	}
+
+	binDir := t.TempDir()
+	for _, name := range names {
+		script := "#!/bin/sh\necho '" + name + " 1.0.0'\n"
+		if err := os.WriteFile(filepath.Join(binDir, name), []byte(script), 0700); err != nil {
+			t.Fatalf("Failed to write fake %s: %v", name, err)
+		}
+	}
+	t.Setenv("PATH", binDir)
+}
+
+func runSetupAllCommand(t *testing.T, stdin string, args ...string) (string, string, error) {
+	t.Helper()
+	defer func() {
+		resetSetupFlags()
+		_ = setupAllCmd.Flags().Set(outputFlagName, "")
+	}()
+
+	var outBuf, errBuf bytes.Buffer
+	root := &cobra.Command{Use: "costa", SilenceErrors: true, SilenceUsage: true}
+	root.PersistentFlags().StringP(outputFlagName, "o", "", outputFlagUsage)
+	root.AddCommand(setupCmd)
+	root.SetOut(&outBuf)
+	root.SetErr(&errBuf)
+	root.SetIn(strings.NewReader(stdin))
+	root.SetArgs(args)
+
+	err := root.Execute()
+	return outBuf.String(), errBuf.String(), err
+}
+
+func TestSetupAll_ConfiguresInstalledTools(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+	installFakeTools(t, "claude")
+
+	output, _, err := runSetupAllCommand(t, "y\n", "setup", "all", "--token", "all-token")
+	if err != nil {
+		t.Fatalf("Command failed: %v\n%s", err, output)
+	}
+
+	// One combined plan and a single confirmation
+	if strings.Count(output, "[Y/n]") != 1 {
+		t.Errorf("expected exactly one prompt, got:\n%s", output)
+	}
+	if !strings.Contains(output, "📝 Claude Code") || !strings.Contains(output, "env.ANTHROPIC_AUTH_TOKEN") {
+		t.Errorf("expected Claude Code changes in the plan, got:\n%s", output)
+	}
+	if !strings.Contains(output, "Claude Code   ✅ configured") || !strings.Contains(output, "Codex         – not installed") {
+		t.Errorf("expected per-tool summary, got:\n%s", output)
+	}
+
+	data, err := os.ReadFile(filepath.Join(tmpDir, ".claude", "settings.json"))
+	if err != nil {
+		t.Fatalf("Failed to read settings: %v", err)
+	}
+	if !strings.Contains(string(data), "all-token") || !strings.Contains(string(data), "statusLine") {
+		t.Errorf("expected token and status line in settings, got:\n%s", data)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, ".codex", "config.toml")); err == nil {
+		t.Errorf("expected Codex to be skipped when not installed")
+	}
+}
+
+func TestSetup_WithoutAppOnlyShowsHelp(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+	installFakeTools(t, "claude")
+
+	// An empty stdin must not count as a yes to configuring every tool
+	output, _, err := runSetupAllCommand(t, "", "setup")
+	if err != nil {
+		t.Fatalf("Command failed: %v\n%s", err, output)
+	}
+	if !strings.Contains(output, "costa setup all") {
+		t.Errorf("expected help pointing at 'costa setup all', got:\n%s", output)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, ".claude", "settings.json")); err == nil {
+		t.Error("expected no settings to be written without 'all'")
+	}
+}
+
+func TestSetupAll_JSONReport(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+	installFakeTools(t, "claude", "codex")
+
+	output, stderr, err := runSetupAllCommand(t, "", "setup", "all", "--token", "all-token", "--force", "--format", "json")
+	if err != nil {
+		t.Fatalf("Command failed: %v\n%s", err, stderr)
+	}
+
+	var result setupAllResult
+	if err := json.Unmarshal([]byte(output), &result); err != nil {
+		t.Fatalf("failed to parse JSON output: %v\noutput: %s", err, output)
+	}
+	if result.Status != "ok" || len(result.Tools) != 2 {
+		t.Fatalf("unexpected report: %+v", result)
+	}
+	for _, tool := range result.Tools {
+		if tool.Status != setupToolConfigured || tool.Version == "" || len(tool.Changes) == 0 {
+			t.Errorf("expected %s to be configured with changes, got %+v", tool.Name, tool)
+		}
+		if len(tool.Files) == 0 || strings.Contains(tool.Files[0].Diff, "all-token") {
+			t.Errorf("expected redacted file diffs for %s, got %+v", tool.Name, tool.Files)
+		}
+	}
+
+	// A second run has nothing to do
+	output, _, err = runSetupAllCommand(t, "", "setup", "all", "--token", "all-token", "-o", "json")
+	if err != nil {
+		t.Fatalf("Command failed: %v", err)
+	}
+	if strings.Count(output, setupToolUpToDate) != 2 {
+		t.Errorf("expected both tools to be already configured, got:\n%s", output)
+	}
+}
+
+func TestSetupAll_DeclineDoesNotWrite(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+	installFakeTools(t, "claude", "codex")
+
+	_, _, err := runSetupAllCommand(t, "n\n", "setup", "all", "--token", "all-token")
+	if ExitCode(err) != ExitCanceled {
+		t.Fatalf("expected canceled exit code, got %d (%v)", ExitCode(err), err)
+	}
+	for _, path := range []string{filepath.Join(".claude", "settings.json"), filepath.Join(".codex", "config.toml")} {
+		if _, err := os.Stat(filepath.Join(tmpDir, path)); err == nil {
+			t.Errorf("expected %s not to be written after declining", path)
+		}
+	}
+}
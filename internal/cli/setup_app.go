@@ -0,0 +1,182 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/costa-app/costa-cli/internal/integrations"
+)
+
+// setupAppCommand is a 'costa setup <app>' command generated from a registration
+type setupAppCommand struct {
+	reg     integrations.Registration
+	format  string
+	opts    integrations.ApplyOpts
+	user    bool
+	project bool
+}
+
+// newSetupAppCommand builds the setup subcommand for a registered integration
+func newSetupAppCommand(reg integrations.Registration) *cobra.Command {
+	s := &setupAppCommand{reg: reg}
+
+	cmd := &cobra.Command{
+		Use:     reg.Name,
+		Aliases: reg.Aliases,
+		Short:   fmt.Sprintf("Setup %s to use Costa", reg.Title),
+		Long:    fmt.Sprintf("Configure %s to use Costa's API and token.", reg.Title),
+		Args:    cobra.NoArgs,
+		RunE:    s.run,
+	}
+
+	flags := cmd.Flags()
+	if reg.SupportsScope(integrations.ScopeProject) {
+		flags.BoolVar(&s.user, "user", false, "Setup for current user (default)")
+		flags.BoolVar(&s.project, "project", false, "Setup for current project")
+	}
+	flags.StringVar(&s.opts.TokenOverride, "token", "", "Use explicit token instead of fetching from Costa")
+	flags.BoolVar(&s.opts.Force, "force", false, "Skip confirmation prompt (auto-yes)")
+	flags.BoolVar(&s.opts.DryRun, "dry-run", false, "Show what would change without writing")
+	flags.StringVar(&s.opts.BackupDir, "backup-dir", "", "Custom backup directory")
+	flags.StringVar(&s.format, legacyFormatFlag, "", "Output format (json); alias for --output json")
+	if reg.Flags != nil {
+		reg.Flags(flags, &s.opts)
+	}
+	return cmd
+}
+
+func (s *setupAppCommand) run(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	reg := s.reg
+	human := outputFormat(cmd) == ""
+
+	// Machine-readable output keeps stdout for the result
+	out := cmd.OutOrStdout()
+	if !human {
+		out = cmd.ErrOrStderr()
+	}
+
+	// Use a single reader for all prompts to avoid buffering issues
+	inputReader := bufio.NewReader(cmd.InOrStdin())
+
+	opts := s.opts
+	opts.Scope = reg.Scopes[0]
+	if s.project {
+		opts.Scope = integrations.ScopeProject
+	}
+
+	integration := reg.New()
+
+	// Get status first to show context
+	status, err := integration.Status(ctx, opts.Scope)
+	if err != nil {
+		return fmt.Errorf("failed to check status: %w", err)
+	}
+
+	// Show detection info
+	if reg.Detect != nil {
+		if status.Installed {
+			fmt.Fprintf(out, "✓ %s detected: %s\n", reg.ToolName, status.Version)
+		} else {
+			if opts.RequireInstalled {
+				return fmt.Errorf("%s not found; install it first: %s", reg.ToolName, reg.InstallURL)
+			}
+			fmt.Fprintf(cmd.ErrOrStderr(), "⚠ %s not detected (will configure anyway)\n", reg.ToolName)
+		}
+	}
+
+	fmt.Fprintf(out, "📁 Config path: %s\n", status.ConfigPath)
+
+	// Phase 1: plan (dry run) to compute changes without writing
+	planOpts := opts
+	planOpts.DryRun = true
+	planResult, err := integration.Apply(ctx, planOpts)
+	if err != nil {
+		return err
+	}
+
+	// Check if already configured
+	if !planResult.Changed {
+		if !human {
+			return printResult(cmd, newSetupAppResult(reg, opts.Scope, planResult, opts.DryRun))
+		}
+		fmt.Fprintln(out, "✓ Already configured! No changes needed.")
+		return nil
+	}
+
+	// Honor --dry-run (show but do not write)
+	if opts.DryRun {
+		if !human {
+			return printResult(cmd, newSetupAppResult(reg, opts.Scope, planResult, true))
+		}
+		fmt.Fprintln(out, "\n📝 Changes to apply:")
+		printPlan(out, planResult)
+		fmt.Fprintln(out, "\n🔍 Dry run - no changes made")
+		return nil
+	}
+
+	// Show planned changes
+	fmt.Fprintln(out, "\n📝 Changes to apply:")
+	printPlan(out, planResult)
+
+	// Ask the integration's optional questions unless flags answered them
+	for _, prompt := range reg.Prompts {
+		if prompt.Skip != nil && prompt.Skip(&opts) {
+			continue
+		}
+		fmt.Fprintf(out, "\n%s [Y/n]: ", prompt.Question)
+		response, _ := inputReader.ReadString('\n')
+		resp := strings.ToLower(strings.TrimSpace(response))
+		if resp == "n" || resp == "no" { // default YES
+			continue
+		}
+		prompt.Accept(&opts)
+
+		// Re-plan with the accepted option
+		planOpts = opts
+		planOpts.DryRun = true
+		planResult, err = integration.Apply(ctx, planOpts)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintln(out, "\n📝 Updated changes to apply:")
+		printPlan(out, planResult)
+	}
+
+	// Confirm if not --force
+	if !opts.Force {
+		fmt.Fprint(out, "\nProceed with changes? [Y/n]: ")
+		response, _ := inputReader.ReadString('\n')
+		resp := strings.ToLower(strings.TrimSpace(response))
+		if resp == "n" || resp == "no" { // default YES
+			fmt.Fprintln(out, "Canceled.")
+			return errCanceled
+		}
+	}
+
+	// Phase 2: write (actual apply)
+	writeOpts := opts
+	writeOpts.DryRun = false
+	result, err := integration.Apply(ctx, writeOpts)
+	if err != nil {
+		return err
+	}
+	if !human {
+		return printResult(cmd, newSetupAppResult(reg, opts.Scope, result, false))
+	}
+
+	for _, backupPath := range result.BackupPaths {
+		fmt.Fprintf(out, "💾 Backup created: %s\n", backupPath)
+	}
+	for _, path := range result.Committed {
+		fmt.Fprintf(out, "✓ Updated %s\n", path)
+	}
+	printWriteWarnings(out, planResult, result)
+
+	fmt.Fprintf(out, "✅ Successfully configured %s for Costa!\n", reg.Title)
+	return nil
+}
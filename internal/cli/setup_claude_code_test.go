@@ -33,10 +33,7 @@ func TestSetupClaudeCode_DryRun(t *testing.T) {
 	root.SetArgs([]string{"setup", "claude-code", "--token", "test-token", "--dry-run"})
 
 	// Reset flags after test
-	defer func() {
-		ccSetupDryRun = false
-		ccSetupToken = ""
-	}()
+	defer resetSetupFlags()
 
 	err := root.Execute()
 	if err != nil {
@@ -91,11 +88,7 @@ func TestSetupClaudeCode_ForceSkipsPrompts(t *testing.T) {
 	root.SetArgs([]string{"setup", "claude-code", "--token", "test-token", "--force", "--skip-statusline"})
 
 	// Reset flags after test
-	defer func() {
-		ccSetupForce = false
-		ccSetupToken = ""
-		ccSetupSkipStatusLine = false
-	}()
+	defer resetSetupFlags()
 
 	err := root.Execute()
 	if err != nil {
@@ -193,11 +186,7 @@ func TestSetupClaudeCode_RefreshTokenOnly(t *testing.T) {
 	root.SetArgs([]string{"setup", "claude-code", "--token", "new-token", "--force", "--refresh-token-only"})
 
 	// Reset flags after test
-	defer func() {
-		ccSetupForce = false
-		ccSetupToken = ""
-		ccSetupRefreshTokenOnly = false
-	}()
+	defer resetSetupFlags()
 
 	err := root.Execute()
 	if err != nil {
@@ -295,10 +284,7 @@ func TestSetupClaudeCode_AlreadyConfigured(t *testing.T) {
 	root.SetArgs([]string{"setup", "claude-code", "--token", "test-token", "--skip-statusline"})
 
 	// Reset flags after test
-	defer func() {
-		ccSetupToken = ""
-		ccSetupSkipStatusLine = false
-	}()
+	defer resetSetupFlags()
 
 	err := root.Execute()
 	if err != nil {
@@ -9,6 +9,8 @@ import (
 
 	"github.com/pelletier/go-toml/v2"
 	"github.com/spf13/cobra"
+
+	"github.com/costa-app/costa-cli/internal/auth"
 )
 
 func TestSetupCodex_DryRun(t *testing.T) {
@@ -33,10 +35,7 @@ func TestSetupCodex_DryRun(t *testing.T) {
 	root.SetArgs([]string{"setup", "codex", "--token", "test-token", "--dry-run"})
 
 	// Reset flags after test
-	defer func() {
-		cdSetupDryRun = false
-		cdSetupToken = ""
-	}()
+	defer resetSetupFlags()
 
 	err := root.Execute()
 	if err != nil {
@@ -91,10 +90,7 @@ func TestSetupCodex_ForceSkipsPrompts(t *testing.T) {
 	root.SetArgs([]string{"setup", "codex", "--token", "test-token", "--force"})
 
 	// Reset flags after test
-	defer func() {
-		cdSetupForce = false
-		cdSetupToken = ""
-	}()
+	defer resetSetupFlags()
 
 	err := root.Execute()
 	if err != nil {
@@ -195,13 +191,11 @@ func TestSetupCodex_DeclinePrompt_DoesNotWrite(t *testing.T) {
 	root.SetArgs([]string{"setup", "codex", "--token", "new-token-different"})
 
 	// Reset flags after test
-	defer func() {
-		cdSetupToken = ""
-	}()
+	defer resetSetupFlags()
 
 	err = root.Execute()
-	if err != nil {
-		t.Fatalf("Command failed: %v", err)
+	if ExitCode(err) != ExitCanceled {
+		t.Fatalf("Expected canceled error (exit %d), got: %v", ExitCanceled, err)
 	}
 
 	output := outBuf.String()
@@ -301,9 +295,7 @@ func TestSetupCodex_AlreadyConfigured(t *testing.T) {
 	root.SetArgs([]string{"setup", "codex", "--token", "test-token"})
 
 	// Reset flags after test
-	defer func() {
-		cdSetupToken = ""
-	}()
+	defer resetSetupFlags()
 
 	err = root.Execute()
 	if err != nil {
@@ -322,3 +314,128 @@ func TestSetupCodex_AlreadyConfigured(t *testing.T) {
 		t.Errorf("Should not show proceed prompt when already configured, got:\n%s", output)
 	}
 }
+
+func TestSetupCodex_BackupDir(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	cfgPath := filepath.Join(home, ".codex", "config.toml")
+	if err := os.MkdirAll(filepath.Dir(cfgPath), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(cfgPath, []byte("model = \"gpt-5\"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	backupDir := filepath.Join(home, "my-backups")
+	out, err := runSetupCommand(t, "codex", "--token", "test-token", "--force", "--backup-dir", backupDir)
+	if err != nil {
+		t.Fatalf("setup failed: %v\n%s", err, out)
+	}
+	if !strings.Contains(out, "Backup created: "+backupDir) {
+		t.Errorf("expected a backup in %s, got:\n%s", backupDir, out)
+	}
+	matches, _ := filepath.Glob(filepath.Join(backupDir, "config-*.toml"))
+	if len(matches) != 1 {
+		t.Fatalf("expected one backup in %s, got %v", backupDir, matches)
+	}
+	if data, _ := os.ReadFile(matches[0]); string(data) != "model = \"gpt-5\"\n" {
+		t.Errorf("backup = %q, want the original config", data)
+	}
+}
+
+func TestSetupCodex_PreservesCommentsAndLayout(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	configPath := filepath.Join(home, ".codex", "config.toml")
+	if err := os.MkdirAll(filepath.Dir(configPath), 0700); err != nil {
+		t.Fatal(err)
+	}
+	original := `# Personal Codex settings
+model = "gpt-5"   # default model
+approval_policy = "on-request"
+
+# Trusted projects
+[projects."/home/me/src/app"]
+trust_level = "trusted"
+
+[mcp_servers.docs]
+command = "docs-mcp"
+args = ["--port", "8080"]
+`
+	if err := os.WriteFile(configPath, []byte(original), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if out, err := runSetupCommand(t, "codex", "--token", "test-token", "--force"); err != nil {
+		t.Fatalf("setup failed: %v\n%s", err, out)
+	}
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `# Personal Codex settings
+model = "costa/auto"   # default model
+approval_policy = "on-request"
+model_provider = "costa"
+
+# Trusted projects
+[projects."/home/me/src/app"]
+trust_level = "trusted"
+
+[mcp_servers.docs]
+command = "docs-mcp"
+args = ["--port", "8080"]
+
+[features]
+web_search_request = true
+
+[model_providers.costa]
+name = "costa"
+base_url = "` + auth.GetBaseURL() + `/api/v1"
+experimental_bearer_token = "test-token"
+`
+	if string(data) != want {
+		t.Errorf("config after setup:\n%s\nwant:\n%s", data, want)
+	}
+
+	if out, err := runSetupCommand(t, "remove", "codex", "--force"); err != nil {
+		t.Fatalf("remove failed: %v\n%s", err, out)
+	}
+	data, err = os.ReadFile(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != original {
+		t.Errorf("config after remove:\n%s\nwant the original:\n%s", data, original)
+	}
+}
+
+func TestSetupCodex_InlineTableIsNotRewritten(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	configPath := filepath.Join(home, ".codex", "config.toml")
+	if err := os.MkdirAll(filepath.Dir(configPath), 0700); err != nil {
+		t.Fatal(err)
+	}
+	original := "# comment\nmodel_providers = { costa = { name = \"costa\", env_key = \"COSTA\" } }\n"
+	if err := os.WriteFile(configPath, []byte(original), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	// Even --force must not replace the file and lose its comments
+	out, err := runSetupCommand(t, "codex", "--token", "test-token", "--force")
+	if err == nil || !strings.Contains(err.Error(), "cannot be edited in place") {
+		t.Fatalf("expected an in-place edit error, got %v\n%s", err, out)
+	}
+
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != original {
+		t.Errorf("expected the config to be left alone, got:\n%s", data)
+	}
+}
@@ -0,0 +1,90 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/costa-app/costa-cli/internal/integrations"
+)
+
+// setupListEntry describes one integration in 'costa setup list'
+type setupListEntry struct {
+	// Installed is nil when the integration cannot detect its tool
+	Installed *bool    `json:"installed,omitempty"`
+	Name      string   `json:"name"`
+	Title     string   `json:"title"`
+	Version   string   `json:"version,omitempty"`
+	Aliases   []string `json:"aliases"`
+	Scopes    []string `json:"scopes"`
+}
+
+// setupListResult is the output of 'costa setup list'
+type setupListResult struct {
+	Status string           `json:"status"`
+	Apps   []setupListEntry `json:"apps"`
+}
+
+func (r setupListResult) table() ([]string, [][]string) {
+	headers := []string{"NAME", "TITLE", "ALIASES", "SCOPES", "INSTALLED"}
+	var rows [][]string
+	for _, app := range r.Apps {
+		rows = append(rows, []string{
+			app.Name,
+			app.Title,
+			strings.Join(app.Aliases, ", "),
+			strings.Join(app.Scopes, ", "),
+			formatInstalled(app.Installed, app.Version),
+		})
+	}
+	return headers, rows
+}
+
+func (r setupListResult) printHuman(out io.Writer) error {
+	if err := writeTable(out, r); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "\nRun 'costa setup <app>' to configure one.\n")
+	return nil
+}
+
+// formatInstalled describes a detected tool, or "-" when it cannot be detected
+func formatInstalled(installed *bool, version string) string {
+	switch {
+	case installed == nil:
+		return "-"
+	case *installed:
+		return "✓ " + version
+	default:
+		return "✗"
+	}
+}
+
+var setupListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the apps costa can configure",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		result := setupListResult{Status: "ok", Apps: []setupListEntry{}}
+		for _, reg := range integrations.All() {
+			entry := setupListEntry{
+				Name:    reg.Name,
+				Title:   reg.Title,
+				Aliases: []string{},
+				Scopes:  []string{},
+			}
+			entry.Aliases = append(entry.Aliases, reg.Aliases...)
+			for _, scope := range reg.Scopes {
+				entry.Scopes = append(entry.Scopes, string(scope))
+			}
+			if reg.Detect != nil {
+				version, installed := reg.Detect()
+				entry.Installed, entry.Version = &installed, version
+			}
+			result.Apps = append(result.Apps, entry)
+		}
+		return printResult(cmd, result)
+	},
+}
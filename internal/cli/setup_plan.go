@@ -0,0 +1,120 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/costa-app/costa-cli/internal/diff"
+	"github.com/costa-app/costa-cli/internal/integrations"
+)
+
+// changeMarkers prefix each key in a plan by what the change does to it
+var changeMarkers = map[integrations.ChangeAction]string{
+	integrations.ChangeAdd:    "+",
+	integrations.ChangeUpdate: "~",
+	integrations.ChangeRemove: "-",
+}
+
+// setupPlanFile is one file's changes as a unified diff
+type setupPlanFile struct {
+	Path string `json:"path"`
+	Diff string `json:"diff"`
+}
+
+// setupAppResult is the machine-readable output of 'costa setup <app>' and
+// 'costa setup remove <app>'
+type setupAppResult struct {
+	Status      string                `json:"status"`
+	App         string                `json:"app"`
+	Scope       string                `json:"scope"`
+	ConfigPath  string                `json:"config_path"`
+	Changes     []integrations.Change `json:"changes"`
+	Files       []setupPlanFile       `json:"files"`
+	BackupPaths []string              `json:"backup_paths,omitempty"`
+	Committed   []string              `json:"committed,omitempty"`
+	Warnings    []string              `json:"warnings,omitempty"`
+	DryRun      bool                  `json:"dry_run"`
+	Changed     bool                  `json:"changed"`
+}
+
+// newSetupAppResult converts an integration's plan or apply result into command output
+func newSetupAppResult(reg integrations.Registration, scope integrations.Scope, result integrations.ApplyResult, dryRun bool) setupAppResult {
+	out := setupAppResult{
+		Status:      "ok",
+		App:         reg.Name,
+		Scope:       string(scope),
+		ConfigPath:  result.ConfigPath,
+		Changes:     append([]integrations.Change{}, result.Changes...),
+		Files:       []setupPlanFile{},
+		BackupPaths: result.BackupPaths,
+		Committed:   result.Committed,
+		Warnings:    result.Warnings,
+		DryRun:      dryRun,
+		Changed:     result.Changed,
+	}
+	for _, d := range result.Diffs {
+		out.Files = append(out.Files, setupPlanFile{Path: d.Path, Diff: unifiedDiff(d)})
+	}
+	return out
+}
+
+// unifiedDiff renders a file's changes as a unified diff
+func unifiedDiff(d integrations.FileDiff) string {
+	return diff.Unified(d.Path, d.Path, d.Before, d.After, 3)
+}
+
+// printPlan lists the keys a plan changes and any warnings, followed by a
+// diff of each file
+func printPlan(out io.Writer, result integrations.ApplyResult) {
+	for _, change := range result.Changes {
+		fmt.Fprintf(out, "  %s %s\n", changeMarkers[change.Action], change.Path)
+	}
+
+	for _, warning := range result.Warnings {
+		fmt.Fprintf(out, "  ⚠ %s\n", warning)
+	}
+
+	color := colorEnabled(out)
+	for _, d := range result.Diffs {
+		fmt.Fprintln(out)
+		printDiff(out, unifiedDiff(d), color)
+	}
+}
+
+// printWriteWarnings prints the warnings raised while writing files, which
+// the plan did not show already
+func printWriteWarnings(out io.Writer, plan, applied integrations.ApplyResult) {
+	for _, warning := range applied.Warnings {
+		if !slices.Contains(plan.Warnings, warning) {
+			fmt.Fprintf(out, "⚠ %s\n", warning)
+		}
+	}
+}
+
+// printDiff writes a unified diff, coloring added and removed lines if color is set
+func printDiff(out io.Writer, text string, color bool) {
+	for _, line := range diff.SplitLines(text) {
+		if color {
+			switch {
+			case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+				line = colorize("bold", line)
+			case strings.HasPrefix(line, "@@"):
+				line = colorize("cyan", line)
+			case strings.HasPrefix(line, "+"):
+				line = colorize("green", line)
+			case strings.HasPrefix(line, "-"):
+				line = colorize("red", line)
+			}
+		}
+		fmt.Fprintln(out, line)
+	}
+}
+
+// colorEnabled reports whether out is a terminal that should get colored output
+func colorEnabled(out io.Writer) bool {
+	f, ok := out.(*os.File)
+	return ok && terminalWidth(f) > 0 && os.Getenv("NO_COLOR") == ""
+}
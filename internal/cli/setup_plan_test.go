@@ -0,0 +1,84 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSetupCodex_DryRunJSONPlan(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	cfgPath := filepath.Join(home, ".codex", "config.toml")
+	if err := os.MkdirAll(filepath.Dir(cfgPath), 0700); err != nil {
+		t.Fatal(err)
+	}
+	original := "model = \"gpt-5\"\n\n[model_providers.costa]\nexperimental_bearer_token = \"sk-old-secret-token\"\nenv_key = \"COSTA_TOKEN\"\n"
+	if err := os.WriteFile(cfgPath, []byte(original), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := runSetupCommand(t, "codex", "--token", "sk-new-secret-token", "--dry-run", "--format", "json")
+	if err != nil {
+		t.Fatalf("setup failed: %v\n%s", err, out)
+	}
+
+	// Progress messages go to stderr; the JSON result is the last line
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	var result setupAppResult
+	if err := json.Unmarshal([]byte(lines[len(lines)-1]), &result); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if !result.DryRun || !result.Changed || result.App != "codex" {
+		t.Errorf("unexpected result %+v", result)
+	}
+
+	actions := map[string]string{}
+	for _, c := range result.Changes {
+		actions[c.Path] = string(c.Action)
+		if c.Path == "model_providers.costa.experimental_bearer_token" && (c.Old != "sk-old****oken" || c.New != "sk-new****oken") {
+			t.Errorf("expected redacted token values, got %+v", c)
+		}
+	}
+	want := map[string]string{
+		"model":                         "update",
+		"model_provider":                "add",
+		"model_providers.costa.env_key": "remove",
+	}
+	for path, action := range want {
+		if actions[path] != action {
+			t.Errorf("expected %s to be %s, got %q", path, action, actions[path])
+		}
+	}
+
+	if len(result.Files) != 1 || !strings.Contains(result.Files[0].Diff, "+model = \"costa/auto\"") {
+		t.Errorf("expected a diff of config.toml, got %+v", result.Files)
+	}
+	if strings.Contains(out, "secret-token\"") || strings.Contains(out, "sk-new-secret-token") {
+		t.Errorf("expected tokens to be redacted, got:\n%s", out)
+	}
+
+	if data, _ := os.ReadFile(cfgPath); string(data) != original {
+		t.Error("expected dry run not to modify the config")
+	}
+}
+
+func TestSetupClaudeCode_DryRunShowsDiff(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	out, err := runSetupCommand(t, "claude-code", "--token", "sk-new-secret-token", "--dry-run", "--skip-statusline")
+	if err != nil {
+		t.Fatalf("setup failed: %v\n%s", err, out)
+	}
+	for _, want := range []string{"+ env.ANTHROPIC_AUTH_TOKEN", "@@ -0,0 +1,", `+    "ANTHROPIC_AUTH_TOKEN": "sk-new****oken"`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output, got:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "sk-new-secret-token") {
+		t.Errorf("expected the token to be redacted, got:\n%s", out)
+	}
+}
@@ -0,0 +1,128 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/costa-app/costa-cli/internal/integrations"
+)
+
+var (
+	setupRemoveBackupDir string
+	setupRemoveFormat    string
+	setupRemoveForce     bool
+	setupRemoveDryRun    bool
+	setupRemoveProject   bool
+)
+
+var setupRemoveCmd = &cobra.Command{
+	Use:   "remove <app>",
+	Short: "Revert an app's Costa configuration",
+	Long: `Remove the settings 'costa setup' added to an app's config. Values the app
+had before Costa was configured, such as a previous model, are restored and
+settings the user changed since are left alone. Other settings are never
+touched, and the config is backed up before it is rewritten.`,
+	Example: `  costa setup remove claude-code --dry-run
+  costa setup remove codex --force`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSetupRemove,
+}
+
+func init() {
+	setupRemoveCmd.Flags().BoolVar(&setupRemoveProject, "project", false, "Revert the project config instead of the user config")
+	setupRemoveCmd.Flags().BoolVar(&setupRemoveForce, "force", false, "Skip confirmation prompt (auto-yes)")
+	setupRemoveCmd.Flags().BoolVar(&setupRemoveDryRun, "dry-run", false, "Show what would change without writing")
+	setupRemoveCmd.Flags().StringVar(&setupRemoveBackupDir, "backup-dir", "", "Directory for the backup (default: ~/.config/costa/backups/<app>)")
+	setupRemoveCmd.Flags().StringVar(&setupRemoveFormat, "format", "", "Output format (json); alias for --output json")
+}
+
+func runSetupRemove(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	human := outputFormat(cmd) == ""
+
+	// Machine-readable output keeps stdout for the result
+	out := cmd.OutOrStdout()
+	if !human {
+		out = cmd.ErrOrStderr()
+	}
+
+	reg, err := lookupIntegration(args[0])
+	if err != nil {
+		return err
+	}
+
+	opts := integrations.ApplyOpts{
+		Scope:     reg.Scopes[0],
+		BackupDir: setupRemoveBackupDir,
+	}
+	if setupRemoveProject {
+		if !reg.SupportsScope(integrations.ScopeProject) {
+			return &CLIError{
+				Code:    ErrCodeUsage,
+				Message: fmt.Sprintf("%s has no project config", reg.Title),
+			}
+		}
+		opts.Scope = integrations.ScopeProject
+	}
+
+	integration := reg.New()
+
+	// Phase 1: plan (dry run) to compute changes without writing
+	planOpts := opts
+	planOpts.DryRun = true
+	plan, err := integration.Remove(ctx, planOpts)
+	if err != nil {
+		return err
+	}
+
+	if !human && (!plan.Changed || setupRemoveDryRun) {
+		return printResult(cmd, newSetupAppResult(reg, opts.Scope, plan, setupRemoveDryRun))
+	}
+
+	fmt.Fprintf(out, "📁 Config path: %s\n", plan.ConfigPath)
+	if !plan.Changed {
+		fmt.Fprintf(out, "✓ %s has no Costa settings to remove.\n", reg.Title)
+		return nil
+	}
+
+	fmt.Fprintln(out, "\n📝 Changes to apply:")
+	printPlan(out, plan)
+
+	if setupRemoveDryRun {
+		fmt.Fprintln(out, "\n🔍 Dry run - no changes made")
+		return nil
+	}
+
+	if !setupRemoveForce {
+		fmt.Fprint(out, "\nProceed with changes? [Y/n]: ")
+		response, _ := bufio.NewReader(cmd.InOrStdin()).ReadString('\n')
+		resp := strings.ToLower(strings.TrimSpace(response))
+		if resp == "n" || resp == "no" { // default YES
+			fmt.Fprintln(out, "Canceled.")
+			return errCanceled
+		}
+	}
+
+	// Phase 2: write
+	result, err := integration.Remove(ctx, opts)
+	if err != nil {
+		return err
+	}
+	if !human {
+		return printResult(cmd, newSetupAppResult(reg, opts.Scope, result, false))
+	}
+
+	for _, backupPath := range result.BackupPaths {
+		fmt.Fprintf(out, "💾 Backup created: %s\n", backupPath)
+	}
+	for _, path := range result.Committed {
+		fmt.Fprintf(out, "✓ Updated %s\n", path)
+	}
+	printWriteWarnings(out, plan, result)
+
+	fmt.Fprintf(out, "✅ Removed Costa from %s\n", reg.Title)
+	return nil
+}
@@ -0,0 +1,282 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pelletier/go-toml/v2"
+	"github.com/spf13/cobra"
+
+	"github.com/costa-app/costa-cli/internal/integrations"
+)
+
+func runSetupCommand(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	defer resetSetupFlags()
+
+	var buf bytes.Buffer
+	root := &cobra.Command{Use: "costa", SilenceErrors: true, SilenceUsage: true}
+	root.PersistentFlags().StringP(outputFlagName, "o", "", outputFlagUsage)
+	root.AddCommand(setupCmd)
+	root.SetOut(&buf)
+	root.SetErr(&buf)
+	root.SetArgs(append([]string{"setup"}, args...))
+	err := root.Execute()
+	return buf.String(), err
+}
+
+func TestSetupRemove_ClaudeCodeRestoresOriginals(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	settingsPath := filepath.Join(home, ".claude", "settings.json")
+	if err := os.MkdirAll(filepath.Dir(settingsPath), 0700); err != nil {
+		t.Fatal(err)
+	}
+	original := `{"model": "opus", "theme": "dark", "env": {"ANTHROPIC_BASE_URL": "https://proxy.example.com", "FOO": "bar"}}`
+	if err := os.WriteFile(settingsPath, []byte(original), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if out, err := runSetupCommand(t, "claude-code", "--token", "test-token", "--force", "--enable-statusline"); err != nil {
+		t.Fatalf("setup failed: %v\n%s", err, out)
+	}
+
+	// Dry run lists the changes without writing
+	before, _ := os.ReadFile(settingsPath)
+	out, err := runSetupCommand(t, "remove", "claude", "--dry-run")
+	if err != nil {
+		t.Fatalf("remove --dry-run failed: %v\n%s", err, out)
+	}
+	if !strings.Contains(out, "Dry run - no changes made") || !strings.Contains(out, "~ model") {
+		t.Errorf("expected planned changes in dry-run output, got:\n%s", out)
+	}
+
+	// The JSON plan describes each reverted key, with the token redacted
+	out, err = runSetupCommand(t, "remove", "claude", "--dry-run", "-o", "json")
+	if err != nil {
+		t.Fatalf("remove --dry-run -o json failed: %v\n%s", err, out)
+	}
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	var plan setupAppResult
+	if err := json.Unmarshal([]byte(lines[len(lines)-1]), &plan); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	changes := map[string]integrations.Change{}
+	for _, c := range plan.Changes {
+		changes[c.Path] = c
+	}
+	if c := changes["model"]; c.Action != integrations.ChangeUpdate || c.Old != "costa/auto" || c.New != "opus" {
+		t.Errorf("expected model restored to opus, got %+v", c)
+	}
+	if c := changes["env.ANTHROPIC_AUTH_TOKEN"]; c.Action != integrations.ChangeRemove || c.Old == "test-token" || c.Old == nil {
+		t.Errorf("expected the token removed with a redacted old value, got %+v", c)
+	}
+	if c := changes["alwaysThinkingEnabled"]; c.Action != integrations.ChangeRemove {
+		t.Errorf("expected alwaysThinkingEnabled removed, got %+v", c)
+	}
+	if strings.Contains(out, "test-token") {
+		t.Errorf("expected the token to be redacted, got:\n%s", out)
+	}
+
+	if after, _ := os.ReadFile(settingsPath); !bytes.Equal(before, after) {
+		t.Error("expected dry run not to modify settings")
+	}
+
+	out, err = runSetupCommand(t, "remove", "claude-code", "--force")
+	if err != nil {
+		t.Fatalf("remove failed: %v\n%s", err, out)
+	}
+	if !strings.Contains(out, "Backup created:") {
+		t.Errorf("expected a backup to be created, got:\n%s", out)
+	}
+
+	data, err := os.ReadFile(settingsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var settings map[string]any
+	if err := json.Unmarshal(data, &settings); err != nil {
+		t.Fatal(err)
+	}
+	if settings["model"] != "opus" || settings["theme"] != "dark" {
+		t.Errorf("expected model and theme restored, got %v", settings)
+	}
+	for _, key := range []string{"alwaysThinkingEnabled", "statusLine"} {
+		if _, ok := settings[key]; ok {
+			t.Errorf("expected %s to be removed", key)
+		}
+	}
+	env, _ := settings["env"].(map[string]any)
+	want := map[string]any{"ANTHROPIC_BASE_URL": "https://proxy.example.com", "FOO": "bar"}
+	if len(env) != len(want) || env["ANTHROPIC_BASE_URL"] != want["ANTHROPIC_BASE_URL"] || env["FOO"] != want["FOO"] {
+		t.Errorf("expected env %v, got %v", want, env)
+	}
+
+	// A second remove has nothing left to do
+	out, err = runSetupCommand(t, "remove", "claude-code", "--force")
+	if err != nil || !strings.Contains(out, "no Costa settings to remove") {
+		t.Errorf("expected nothing to remove, got %v:\n%s", err, out)
+	}
+}
+
+func TestSetupRemove_ClaudeCodeKeepsSwitchedProvider(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	settingsPath := filepath.Join(home, ".claude", "settings.json")
+	if err := os.MkdirAll(filepath.Dir(settingsPath), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(settingsPath, []byte(`{"model": "opus"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if out, err := runSetupCommand(t, "claude-code", "--token", "test-token", "--force", "--skip-statusline"); err != nil {
+		t.Fatalf("setup failed: %v\n%s", err, out)
+	}
+
+	// The user points Claude Code at another provider, keeping the rest
+	data, err := os.ReadFile(settingsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var settings map[string]any
+	if err := json.Unmarshal(data, &settings); err != nil {
+		t.Fatal(err)
+	}
+	env := settings["env"].(map[string]any)
+	env["ANTHROPIC_BASE_URL"] = "https://proxy.example.com"
+	env["ANTHROPIC_AUTH_TOKEN"] = "proxy-token"
+	if data, err = json.Marshal(settings); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(settingsPath, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if out, err := runSetupCommand(t, "remove", "claude-code", "--force"); err != nil {
+		t.Fatalf("remove failed: %v\n%s", err, out)
+	}
+
+	data, err = os.ReadFile(settingsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	settings = map[string]any{}
+	if err := json.Unmarshal(data, &settings); err != nil {
+		t.Fatal(err)
+	}
+	env, _ = settings["env"].(map[string]any)
+	if env["ANTHROPIC_BASE_URL"] != "https://proxy.example.com" || env["ANTHROPIC_AUTH_TOKEN"] != "proxy-token" {
+		t.Errorf("expected the new provider kept, got env %v", env)
+	}
+	if env["DISABLE_PROMPT_CACHING"] != true || settings["alwaysThinkingEnabled"] != true {
+		t.Errorf("expected settings without a Costa-specific value kept, got %v", settings)
+	}
+	if settings["model"] != "opus" {
+		t.Errorf("expected the Costa model reverted, got %v", settings["model"])
+	}
+	if _, ok := env["CLAUDE_CODE_SUBAGENT_MODEL"]; ok {
+		t.Errorf("expected Costa model aliases removed, got env %v", env)
+	}
+}
+
+func TestSetupRemove_CodexKeepsSwitchedProvider(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	cfgPath := filepath.Join(home, ".codex", "config.toml")
+	if out, err := runSetupCommand(t, "codex", "--token", "test-token", "--force"); err != nil {
+		t.Fatalf("setup failed: %v\n%s", err, out)
+	}
+
+	// The user repoints the costa provider entry at their own gateway
+	data, err := os.ReadFile(cfgPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cfg map[string]any
+	if err := toml.Unmarshal(data, &cfg); err != nil {
+		t.Fatal(err)
+	}
+	cfg["model_provider"] = "openai"
+	cfg["model_providers"].(map[string]any)["costa"].(map[string]any)["base_url"] = "https://gateway.example.com/v1"
+	if data, err = toml.Marshal(cfg); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(cfgPath, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if out, err := runSetupCommand(t, "remove", "codex", "--force"); err != nil {
+		t.Fatalf("remove failed: %v\n%s", err, out)
+	}
+
+	data, err = os.ReadFile(cfgPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg = map[string]any{}
+	if err := toml.Unmarshal(data, &cfg); err != nil {
+		t.Fatal(err)
+	}
+	if cfg["model_provider"] != "openai" {
+		t.Errorf("expected model_provider kept, got %v", cfg["model_provider"])
+	}
+	if _, ok := integrations.LookupPath(cfg, "model_providers.costa.experimental_bearer_token"); !ok {
+		t.Errorf("expected the repointed provider kept, got %v", cfg)
+	}
+	if _, ok := integrations.LookupPath(cfg, "features.web_search_request"); !ok {
+		t.Errorf("expected features kept while another provider is selected, got %v", cfg)
+	}
+}
+
+func TestSetupRemove_CodexRemovesProvider(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	cfgPath := filepath.Join(home, ".codex", "config.toml")
+	if err := os.MkdirAll(filepath.Dir(cfgPath), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(cfgPath, []byte("model = \"gpt-5\"\napproval_policy = \"never\"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if out, err := runSetupCommand(t, "codex", "--token", "test-token", "--force"); err != nil {
+		t.Fatalf("setup failed: %v\n%s", err, out)
+	}
+	if out, err := runSetupCommand(t, "remove", "codex", "--force"); err != nil {
+		t.Fatalf("remove failed: %v\n%s", err, out)
+	}
+
+	data, err := os.ReadFile(cfgPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cfg map[string]any
+	if err := toml.Unmarshal(data, &cfg); err != nil {
+		t.Fatal(err)
+	}
+	if cfg["model"] != "gpt-5" || cfg["approval_policy"] != "never" {
+		t.Errorf("expected the user's settings restored, got %v", cfg)
+	}
+	for _, key := range []string{"model_provider", "model_providers", "features"} {
+		if _, ok := cfg[key]; ok {
+			t.Errorf("expected %s to be removed, got %v", key, cfg)
+		}
+	}
+}
+
+func TestSetupRemove_UnknownApp(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	_, err := runSetupCommand(t, "remove", "vim")
+	if err == nil || !strings.Contains(err.Error(), "unknown app: vim") {
+		t.Errorf("expected unknown app error, got %v", err)
+	}
+}
@@ -2,16 +2,28 @@ package cli
 
 import (
 	"github.com/spf13/cobra"
+
+	"github.com/costa-app/costa-cli/internal/integrations"
+
+	// Integrations register themselves with the registry in init
+	_ "github.com/costa-app/costa-cli/internal/integrations/claudecode"
+	_ "github.com/costa-app/costa-cli/internal/integrations/codex"
 )
 
 var setupCmd = &cobra.Command{
 	Use:   "setup",
 	Short: "Setup integrations with Costa",
-	Long:  `Setup and configure third-party tools to work with Costa.`,
+	Long: `Setup and configure third-party tools to work with Costa.
+
+Run 'costa setup all' to configure every installed tool in one pass.`,
 }
 
 func init() {
-	setupCmd.AddCommand(setupClaudeCodeCmd)
-	setupCmd.AddCommand(setupCodexCmd)
+	for _, reg := range integrations.All() {
+		setupCmd.AddCommand(newSetupAppCommand(reg))
+	}
+	setupCmd.AddCommand(setupAllCmd)
 	setupCmd.AddCommand(setupStatusCmd)
+	setupCmd.AddCommand(setupListCmd)
+	setupCmd.AddCommand(setupRemoveCmd)
 }
@@ -1,9 +1,25 @@
 package cli
 
 import (
+	"bytes"
+	"encoding/json"
 	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
+// resetSetupFlags restores every setup subcommand's flags to their defaults,
+// since generated commands keep flag values between Execute calls
+func resetSetupFlags() {
+	for _, c := range setupCmd.Commands() {
+		c.Flags().VisitAll(func(f *pflag.Flag) {
+			_ = f.Value.Set(f.DefValue)
+			f.Changed = false
+		})
+	}
+}
+
 func TestSetupRoot_RegistersSubcommands(t *testing.T) {
 	// setupCmd is defined in setup_root.go and should have subcommands wired in init()
 	names := map[string]bool{}
@@ -19,4 +35,82 @@ func TestSetupRoot_RegistersSubcommands(t *testing.T) {
 	if !names["status [app]"] {
 		t.Errorf("expected status subcommand to be registered with Use 'status [app]'")
 	}
+	if !names["list"] {
+		t.Errorf("expected list subcommand to be registered")
+	}
+}
+
+func TestSetupRoot_GeneratedFlags(t *testing.T) {
+	claude, _, err := setupCmd.Find([]string{"claude"})
+	if err != nil || claude.Name() != "claude-code" {
+		t.Fatalf("expected the claude alias to resolve to claude-code, got %v (%v)", claude, err)
+	}
+	for _, name := range []string{"token", "force", "dry-run", "project", "refresh-token-only", "skip-statusline"} {
+		if claude.Flags().Lookup(name) == nil {
+			t.Errorf("expected claude-code to have --%s", name)
+		}
+	}
+
+	// Codex only supports user scope and has no app-specific flags
+	codex, _, err := setupCmd.Find([]string{"codex"})
+	if err != nil {
+		t.Fatalf("expected codex subcommand: %v", err)
+	}
+	for _, name := range []string{"project", "refresh-token-only"} {
+		if codex.Flags().Lookup(name) != nil {
+			t.Errorf("expected codex not to have --%s", name)
+		}
+	}
+}
+
+func TestSetupList_JSON(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("PATH", t.TempDir())
+
+	var buf bytes.Buffer
+	root := &cobra.Command{Use: "costa", SilenceErrors: true, SilenceUsage: true}
+	root.PersistentFlags().StringP(outputFlagName, "o", "", outputFlagUsage)
+	root.AddCommand(setupCmd)
+	root.SetOut(&buf)
+	root.SetErr(&buf)
+	root.SetArgs([]string{"setup", "list", "-o", "json"})
+	defer func() { _ = setupListCmd.Flags().Lookup(outputFlagName).Value.Set("") }()
+
+	if err := root.Execute(); err != nil {
+		t.Fatalf("Command failed: %v", err)
+	}
+
+	var result setupListResult
+	if err := json.Unmarshal(buf.Bytes(), &result); err != nil {
+		t.Fatalf("failed to parse JSON output: %v\noutput: %s", err, buf.String())
+	}
+	if len(result.Apps) != 2 || result.Apps[0].Name != "claude-code" || result.Apps[1].Name != "codex" {
+		t.Fatalf("expected claude-code and codex, got %+v", result.Apps)
+	}
+	for _, app := range result.Apps {
+		if app.Installed == nil || *app.Installed {
+			t.Errorf("expected %s to be detected as not installed, got %v", app.Name, app.Installed)
+		}
+	}
+	if len(result.Apps[0].Scopes) != 2 {
+		t.Errorf("expected claude-code to support user and project scope, got %v", result.Apps[0].Scopes)
+	}
+}
+
+func TestSetupStatus_UnknownAppListsRegistry(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	root := &cobra.Command{Use: "costa", SilenceErrors: true, SilenceUsage: true}
+	root.AddCommand(setupCmd)
+	root.SetOut(&bytes.Buffer{})
+	root.SetErr(&bytes.Buffer{})
+	root.SetArgs([]string{"setup", "status", "vim"})
+
+	err := root.Execute()
+	if ExitCode(err) != ExitUsage {
+		t.Fatalf("expected usage exit code, got %d (%v)", ExitCode(err), err)
+	}
+	if cliErr, ok := err.(*CLIError); !ok || cliErr.Hint != "Supported apps: claude-code, codex." {
+		t.Errorf("expected hint listing registered apps, got %#v", err)
+	}
 }
@@ -4,12 +4,14 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"strconv"
+	"strings"
 
 	"github.com/spf13/cobra"
 
+	"github.com/costa-app/costa-cli/internal/auth"
 	"github.com/costa-app/costa-cli/internal/integrations"
-	"github.com/costa-app/costa-cli/internal/integrations/claudecode"
-	"github.com/costa-app/costa-cli/internal/integrations/codex"
 )
 
 var (
@@ -18,17 +20,78 @@ var (
 	setupStatusFormat string
 )
 
+// appStatusResult is the status of a single integration
+type appStatusResult struct {
+	// Installed is nil when the integration cannot detect its tool
+	Installed      *bool      `json:"installed,omitempty"`
+	Error          *errorBody `json:"error,omitempty"`
+	Status         string     `json:"status,omitempty"`
+	Version        string     `json:"version,omitempty"`
+	Scope          string     `json:"scope,omitempty"`
+	ConfigPath     string     `json:"config_path,omitempty"`
+	Model          string     `json:"model,omitempty"`
+	TokenRedacted  string     `json:"token_redacted,omitempty"`
+	Missing        []string   `json:"missing,omitempty"`
+	ConfigExists   bool       `json:"config_exists"`
+	IsCostaEnabled bool       `json:"is_costa_enabled"`
+	// title, toolName and setupName are used for human-readable output only
+	title     string
+	toolName  string
+	setupName string
+}
+
+// setupStatusResult is the output of 'costa setup status' without an app
+type setupStatusResult struct {
+	// Account is the cached 'costa whoami' identity, if any
+	Account *auth.Identity
+	Status  string
+	// Apps are keyed by setup name in JSON, e.g. "claude_code"
+	Apps []appStatusResult
+}
+
+// MarshalJSON writes each app's status under its own key next to the account
+func (r setupStatusResult) MarshalJSON() ([]byte, error) {
+	fields := map[string]any{"status": r.Status}
+	if r.Account != nil {
+		fields["account"] = r.Account
+	}
+	for _, app := range r.Apps {
+		fields[strings.ReplaceAll(app.setupName, "-", "_")] = app
+	}
+	return json.Marshal(fields)
+}
+
+func (r setupStatusResult) table() ([]string, [][]string) {
+	headers := []string{"APP", "INSTALLED", "CONFIGURED", "COSTA", "CONFIG PATH"}
+	var rows [][]string
+	for _, app := range r.Apps {
+		installed := "-"
+		if app.Installed != nil {
+			installed = strconv.FormatBool(*app.Installed)
+		}
+		rows = append(rows, []string{
+			app.setupName,
+			installed,
+			strconv.FormatBool(app.ConfigExists),
+			strconv.FormatBool(app.IsCostaEnabled),
+			app.ConfigPath,
+		})
+	}
+	return headers, rows
+}
+
 var setupStatusCmd = &cobra.Command{
 	Use:   "status [app]",
 	Short: "Check setup status",
 	Long:  `Check if tools are installed and configured to use Costa. Run without arguments to check all apps.`,
+	Args:  cobra.MaximumNArgs(1),
 	RunE:  runSetupStatus,
 }
 
 func init() {
 	setupStatusCmd.Flags().BoolVar(&setupUser, "user", false, "Check user config (default)")
 	setupStatusCmd.Flags().BoolVar(&setupProject, "project", false, "Check project config")
-	setupStatusCmd.Flags().StringVar(&setupStatusFormat, "format", "", "Output format (json)")
+	setupStatusCmd.Flags().StringVar(&setupStatusFormat, "format", "", "Output format (json); alias for --output json")
 }
 
 func runSetupStatus(cmd *cobra.Command, args []string) error {
@@ -42,229 +105,160 @@ func runSetupStatus(cmd *cobra.Command, args []string) error {
 
 	// If specific app requested
 	if len(args) > 0 {
-		appName := args[0]
-		// Normalize aliases
-		if appName == "claude" || appName == "claude code" {
-			appName = "claude-code"
+		reg, err := lookupIntegration(args[0])
+		if err != nil {
+			return err
 		}
+		return showAppStatus(cmd, ctx, reg, scope)
+	}
 
-		if appName == "claude-code" {
-			return showClaudeCodeStatus(cmd, ctx, scope)
+	result := setupStatusResult{Status: "ok", Account: auth.LoadIdentity()}
+	for _, reg := range integrations.All() {
+		status, err := reg.New().Status(ctx, scope)
+		result.Apps = append(result.Apps, newAppStatusResult(reg, status, err))
+		if err != nil && outputFormat(cmd) == "" {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Error checking %s: %v\n", reg.Title, err)
 		}
-		if appName == "codex" {
-			return showCodexStatus(cmd, ctx, scope)
-		}
-
-		return fmt.Errorf("unknown app: %s", appName)
 	}
 
-	// Check Claude Code
-	claudeStatus, err := claudecode.New().Status(ctx, scope)
-	if err != nil && setupStatusFormat != "json" {
-		fmt.Fprintf(cmd.ErrOrStderr(), "Error checking Claude Code: %v\n", err)
-	}
+	return printResult(cmd, result)
+}
 
-	// Check Codex
-	codexStatus, codexErr := codex.New().Status(ctx, scope)
-	if codexErr != nil && setupStatusFormat != "json" {
-		fmt.Fprintf(cmd.ErrOrStderr(), "Error checking Codex: %v\n", codexErr)
+// lookupIntegration resolves an app name or alias from the command line
+func lookupIntegration(name string) (integrations.Registration, error) {
+	reg, ok := integrations.Lookup(name)
+	if !ok {
+		return reg, &CLIError{
+			Code:    ErrCodeUsage,
+			Message: fmt.Sprintf("unknown app: %s", name),
+			Hint:    fmt.Sprintf("Supported apps: %s.", strings.Join(integrations.Names(), ", ")),
+		}
 	}
+	return reg, nil
+}
 
-	// JSON output
-	if setupStatusFormat == "json" {
-		output := map[string]interface{}{
-			"claude_code": map[string]interface{}{
-				"installed":        claudeStatus.Installed,
-				"version":          claudeStatus.Version,
-				"config_exists":    claudeStatus.ConfigExists,
-				"is_costa_enabled": claudeStatus.IsCosta,
-			},
-			"codex": map[string]interface{}{
-				"config_exists":    codexStatus.ConfigExists,
-				"is_costa_enabled": codexStatus.IsCosta,
-			},
-		}
-		if err != nil {
-			output["error"] = err.Error()
-		}
-		data, jsonErr := json.Marshal(output)
-		if jsonErr != nil {
-			return jsonErr
+func (r setupStatusResult) printHuman(out io.Writer) error {
+	fmt.Fprintln(out, "🔍 Costa Setup Status")
+	if r.Account != nil {
+		account := r.Account.Email
+		if r.Account.Org != nil {
+			account += " · " + formatOrg(r.Account.Org)
 		}
-		fmt.Fprintln(cmd.OutOrStdout(), string(data))
-		return nil
+		fmt.Fprintf(out, "%-16s%s\n", "Account:", account)
 	}
 
-	// Human-readable output
-	fmt.Fprintln(cmd.OutOrStdout(), "🔍 Costa Setup Status")
-
-	if err == nil {
-		fmt.Fprintf(cmd.OutOrStdout(), "Claude Code:    %s\n", formatStatusIcon(claudeStatus.IsCosta))
-		if claudeStatus.Installed {
-			fmt.Fprintf(cmd.OutOrStdout(), "  Installed:    ✓ %s\n", claudeStatus.Version)
-		} else {
-			fmt.Fprintln(cmd.OutOrStdout(), "  Installed:    ✗ Not found")
+	for _, app := range r.Apps {
+		if app.Error != nil {
+			continue
 		}
-		if claudeStatus.ConfigExists {
-			if claudeStatus.IsCosta {
-				fmt.Fprintln(cmd.OutOrStdout(), "  Configured:   ✓ Costa enabled")
+		fmt.Fprintf(out, "%-16s%s\n", app.title+":", formatStatusIcon(app.IsCostaEnabled))
+		if app.Installed != nil {
+			if *app.Installed {
+				fmt.Fprintf(out, "  Installed:    ✓ %s\n", app.Version)
 			} else {
-				fmt.Fprintln(cmd.OutOrStdout(), "  Configured:   ⚠ Partial setup")
+				fmt.Fprintln(out, "  Installed:    ✗ Not found")
 			}
-		} else {
-			fmt.Fprintln(cmd.OutOrStdout(), "  Configured:   ✗ Not configured")
 		}
-	}
-
-	if codexErr == nil {
-		fmt.Fprintf(cmd.OutOrStdout(), "Codex:          %s\n", formatStatusIcon(codexStatus.IsCosta))
-		if codexStatus.ConfigExists {
-			if codexStatus.IsCosta {
-				fmt.Fprintln(cmd.OutOrStdout(), "  Configured:   ✓ Costa enabled")
+		if app.ConfigExists {
+			if app.IsCostaEnabled {
+				fmt.Fprintln(out, "  Configured:   ✓ Costa enabled")
 			} else {
-				fmt.Fprintln(cmd.OutOrStdout(), "  Configured:   ⚠ Partial setup")
+				fmt.Fprintln(out, "  Configured:   ⚠ Partial setup")
 			}
 		} else {
-			fmt.Fprintln(cmd.OutOrStdout(), "  Configured:   ✗ Not configured")
+			fmt.Fprintln(out, "  Configured:   ✗ Not configured")
 		}
 	}
 
-	fmt.Fprintf(cmd.OutOrStdout(), "\nRun 'costa setup status <app>' for details.\n")
+	fmt.Fprintf(out, "\nRun 'costa setup status <app>' for details.\n")
 
 	return nil
 }
 
-func showClaudeCodeStatus(cmd *cobra.Command, ctx context.Context, scope integrations.Scope) error {
-	integration := claudecode.New()
-	status, err := integration.Status(ctx, scope)
-	if err != nil {
-		return fmt.Errorf("failed to check status: %w", err)
-	}
-
-	// JSON output
-	if setupStatusFormat == "json" {
-		output := map[string]interface{}{
-			"installed":        status.Installed,
-			"version":          status.Version,
-			"scope":            string(status.Scope),
-			"config_path":      status.ConfigPath,
-			"config_exists":    status.ConfigExists,
-			"is_costa_enabled": status.IsCosta,
-		}
-		if status.Model != "" {
-			output["model"] = status.Model
-		}
-		if status.TokenRedacted != "" {
-			output["token_redacted"] = status.TokenRedacted
-		}
-		if len(status.Missing) > 0 {
-			output["missing"] = status.Missing
-		}
-		data, jsonErr := json.Marshal(output)
-		if jsonErr != nil {
-			return jsonErr
-		}
-		fmt.Fprintln(cmd.OutOrStdout(), string(data))
-		return nil
-	}
-
-	// Human-readable output
-	fmt.Fprintln(cmd.OutOrStdout(), "🔍 Claude Code Setup Status")
-
-	// Claude CLI
-	if status.Installed {
-		fmt.Fprintf(cmd.OutOrStdout(), "Claude CLI:     ✓ Installed (%s)\n", status.Version)
-	} else {
-		fmt.Fprintln(cmd.OutOrStdout(), "Claude CLI:     ✗ Not found")
+// newAppStatusResult converts an integration status into command output
+func newAppStatusResult(reg integrations.Registration, status integrations.StatusResult, err error) appStatusResult {
+	result := appStatusResult{
+		Version:        status.Version,
+		Scope:          string(status.Scope),
+		ConfigPath:     status.ConfigPath,
+		Model:          status.Model,
+		TokenRedacted:  status.TokenRedacted,
+		Missing:        status.Missing,
+		ConfigExists:   status.ConfigExists,
+		IsCostaEnabled: status.IsCosta,
+		title:          reg.Title,
+		toolName:       reg.ToolName,
+		setupName:      reg.Name,
 	}
 
-	// Config info
-	fmt.Fprintf(cmd.OutOrStdout(), "Config scope:   %s\n", status.Scope)
-	fmt.Fprintf(cmd.OutOrStdout(), "Config path:    %s\n", status.ConfigPath)
-
-	// Config status
-	if !status.ConfigExists {
-		fmt.Fprintln(cmd.OutOrStdout(), "Config status:  ✗ Not configured")
-		fmt.Fprintln(cmd.OutOrStdout(), "Run 'costa setup claude-code' to configure.")
-		return nil
+	// Installation is only reported by integrations that can detect their tool
+	if reg.Detect != nil {
+		installed := status.Installed
+		result.Installed = &installed
 	}
 
-	if status.IsCosta {
-		fmt.Fprintln(cmd.OutOrStdout(), "Config status:  ✓ Configured for Costa")
-
-		// Show current model
-		if status.Model != "" {
-			fmt.Fprintf(cmd.OutOrStdout(), "Model:          %s\n", status.Model)
-		}
-
-		// Check token presence (redacted)
-		if status.TokenRedacted != "" {
-			fmt.Fprintf(cmd.OutOrStdout(), "Token:          %s\n", status.TokenRedacted)
-		}
-	} else {
-		fmt.Fprintln(cmd.OutOrStdout(), "Config status:  ⚠ Partially configured")
-		if len(status.Missing) > 0 {
-			fmt.Fprintln(cmd.OutOrStdout(), "\nMissing Costa settings:")
-			for _, key := range status.Missing {
-				fmt.Fprintf(cmd.OutOrStdout(), "  - %s\n", key)
-			}
-		}
-		fmt.Fprintln(cmd.OutOrStdout(), "\nRun 'costa setup claude-code' to fix.")
+	if err != nil {
+		body := newErrorBody(err)
+		result.Error = &body
 	}
-
-	return nil
+	return result
 }
 
-func showCodexStatus(cmd *cobra.Command, ctx context.Context, scope integrations.Scope) error {
-	integration := codex.New()
-	status, err := integration.Status(ctx, scope)
+func showAppStatus(cmd *cobra.Command, ctx context.Context, reg integrations.Registration, scope integrations.Scope) error {
+	status, err := reg.New().Status(ctx, scope)
 	if err != nil {
 		return fmt.Errorf("failed to check status: %w", err)
 	}
 
-	// JSON output
-	if setupStatusFormat == "json" {
-		output := map[string]interface{}{
-			"scope":            string(status.Scope),
-			"config_path":      status.ConfigPath,
-			"config_exists":    status.ConfigExists,
-			"is_costa_enabled": status.IsCosta,
-		}
-		if status.Model != "" {
-			output["model"] = status.Model
-		}
-		data, jsonErr := json.Marshal(output)
-		if jsonErr != nil {
-			return jsonErr
+	result := newAppStatusResult(reg, status, nil)
+	result.Status = "ok"
+	return printResult(cmd, result)
+}
+
+func (r appStatusResult) printHuman(out io.Writer) error {
+	fmt.Fprintf(out, "🔍 %s Setup Status\n", r.title)
+
+	// Tool installation
+	if r.Installed != nil {
+		if *r.Installed {
+			fmt.Fprintf(out, "%-16s✓ Installed (%s)\n", r.toolName+":", r.Version)
+		} else {
+			fmt.Fprintf(out, "%-16s✗ Not found\n", r.toolName+":")
 		}
-		fmt.Fprintln(cmd.OutOrStdout(), string(data))
-		return nil
 	}
 
-	// Human-readable output
-	fmt.Fprintln(cmd.OutOrStdout(), "🔍 Codex Setup Status")
-
 	// Config info
-	fmt.Fprintf(cmd.OutOrStdout(), "Config scope:   %s\n", status.Scope)
-	fmt.Fprintf(cmd.OutOrStdout(), "Config path:    %s\n", status.ConfigPath)
+	fmt.Fprintf(out, "Config scope:   %s\n", r.Scope)
+	fmt.Fprintf(out, "Config path:    %s\n", r.ConfigPath)
 
 	// Config status
-	if !status.ConfigExists {
-		fmt.Fprintln(cmd.OutOrStdout(), "Config status:  ✗ Not configured")
-		fmt.Fprintln(cmd.OutOrStdout(), "Run 'costa setup codex' to configure.")
+	if !r.ConfigExists {
+		fmt.Fprintln(out, "Config status:  ✗ Not configured")
+		fmt.Fprintf(out, "Run 'costa setup %s' to configure.\n", r.setupName)
 		return nil
 	}
 
-	if status.IsCosta {
-		fmt.Fprintln(cmd.OutOrStdout(), "Config status:  ✓ Configured for Costa")
+	if r.IsCostaEnabled {
+		fmt.Fprintln(out, "Config status:  ✓ Configured for Costa")
 
 		// Show current model
-		if status.Model != "" {
-			fmt.Fprintf(cmd.OutOrStdout(), "Model:          %s\n", status.Model)
+		if r.Model != "" {
+			fmt.Fprintf(out, "Model:          %s\n", r.Model)
+		}
+
+		// Check token presence (redacted)
+		if r.TokenRedacted != "" {
+			fmt.Fprintf(out, "Token:          %s\n", r.TokenRedacted)
 		}
 	} else {
-		fmt.Fprintln(cmd.OutOrStdout(), "Config status:  ⚠ Partially configured")
-		fmt.Fprintln(cmd.OutOrStdout(), "\nRun 'costa setup codex' to fix.")
+		fmt.Fprintln(out, "Config status:  ⚠ Partially configured")
+		if len(r.Missing) > 0 {
+			fmt.Fprintln(out, "\nMissing Costa settings:")
+			for _, key := range r.Missing {
+				fmt.Fprintf(out, "  - %s\n", key)
+			}
+		}
+		fmt.Fprintf(out, "\nRun 'costa setup %s' to fix.\n", r.setupName)
 	}
 
 	return nil
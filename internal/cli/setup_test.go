@@ -71,10 +71,11 @@ func TestSetupClaudeCode_DeclinePrompt_DoesNotWrite(t *testing.T) {
 
 	// Run setup with explicit token (to avoid needing real auth)
 	root.SetArgs([]string{"setup", "claude-code", "--token", "new-token-different"})
+	defer resetSetupFlags()
 
 	err := root.Execute()
-	if err != nil {
-		t.Fatalf("Command failed: %v", err)
+	if ExitCode(err) != ExitCanceled {
+		t.Fatalf("Expected canceled error (exit %d), got: %v", ExitCanceled, err)
 	}
 
 	// Verify output contains the proceed prompt
@@ -2,224 +2,201 @@ package cli
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"io"
-	"net/http"
 	"time"
 
 	"github.com/spf13/cobra"
 
+	"github.com/costa-app/costa-cli/internal/api"
 	"github.com/costa-app/costa-cli/internal/auth"
-	"github.com/costa-app/costa-cli/internal/debug"
+	"github.com/costa-app/costa-cli/internal/config"
 )
 
 var (
-	statusFormat string
+	statusFormat       string
+	statusTemplate     string
+	statusWarnAt       string
+	statusFailAt       string
+	statusWatch        time.Duration
+	statusRefreshCache bool // Internal flag: refresh the usage cache in the background
 )
 
-var statusCmd = &cobra.Command{
-	Use:   "status",
-	Short: "Show Costa CLI status",
-	Long:  `Display the current login status and usage information.`,
-	RunE: func(cmd *cobra.Command, args []string) error {
-		if statusFormat == "claude-code" {
-			return outputStatusClaudeCode(cmd)
-		}
-		if statusFormat == "json" {
-			return outputStatusJSON(cmd)
-		}
-		out := cmd.OutOrStdout()
-
-		// Check login status
-		loggedIn := auth.IsLoggedIn()
-		if loggedIn {
-			fmt.Fprintf(out, "Logged in: yes\n")
-		} else {
-			fmt.Fprintf(out, "Logged in: no\n")
-			return nil
-		}
-
-		// Fetch usage info asynchronously if logged in
-		usageChan := make(chan *UsageInfo)
-		go func() {
-			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-			defer cancel()
-			usage, _ := fetchUsageWithCache(ctx)
-			usageChan <- usage
-		}()
-
-		// Wait for usage info with timeout
-		select {
-		case usage := <-usageChan:
-			if usage != nil {
-				pointsStr := "-"
-				if usage.Points.IsValid {
-					pointsStr = formatPoints(usage.Points.Value)
-				}
-				fmt.Fprintf(out, "Usage: %s / %s points\n", pointsStr, usage.TotalPoints)
-			}
-		case <-time.After(5 * time.Second):
-			// Timeout - just continue without usage info
-		}
-
-		return nil
-	},
+// statusResult is the output of 'costa status'
+type statusResult struct {
+	// Points and TotalPoints are numbers, or "-" when the API has no value yet
+	Points      any             `json:"points,omitempty"`
+	TotalPoints any             `json:"total_points,omitempty"`
+	UsageError  *errorBody      `json:"usage_error,omitempty"`
+	Cache       *usageCacheInfo `json:"cache,omitempty"`
+	Quota       *quotaResult    `json:"quota,omitempty"`
+	Forecast    *usageForecast  `json:"forecast,omitempty"`
+	// Org is the organization selected with 'costa org use', if any
+	Org      *auth.Organization `json:"org,omitempty"`
+	Status   string             `json:"status"`
+	LoggedIn bool               `json:"logged_in"`
 }
 
-func outputStatusJSON(cmd *cobra.Command) error {
-	loggedIn := auth.IsLoggedIn()
-	output := map[string]interface{}{
-		"logged_in": loggedIn,
+func (r statusResult) printHuman(out io.Writer) error {
+	if !r.LoggedIn {
+		fmt.Fprintf(out, "Logged in: no\n")
+		return nil
 	}
-
-	if loggedIn {
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-		defer cancel()
-		usage, err := fetchUsageWithCache(ctx)
-		if err == nil && usage != nil {
-			if usage.Points.IsValid {
-				output["points"] = usage.Points.Value
-			} else {
-				output["points"] = "-"
-			}
-			output["total_points"] = usage.TotalPoints
-		}
+	fmt.Fprintf(out, "Logged in: yes\n")
+	if r.Org != nil {
+		fmt.Fprintf(out, "Organization: %s\n", formatOrg(r.Org))
 	}
 
-	data, err := json.Marshal(output)
-	if err != nil {
-		return err
+	// Usage failures are not fatal in human mode
+	if r.Points != nil {
+		fmt.Fprintf(out, "Usage: %s / %s points", formatPointsValue(r.Points), formatPointsValue(r.TotalPoints))
+		if r.Quota != nil {
+			fmt.Fprintf(out, " (%s%%)", formatPoints(r.Quota.PercentUsed))
+		}
+		fmt.Fprintln(out)
 	}
-
-	fmt.Fprintln(cmd.OutOrStdout(), string(data))
 	return nil
 }
 
-func outputStatusClaudeCode(cmd *cobra.Command) error {
-	out := cmd.OutOrStdout()
-
-	// Check login status
-	if !auth.IsLoggedIn() {
-		fmt.Fprintf(out, "Costa: Not logged in")
-		return nil
+// formatPointsValue formats a points value that may be "-"
+func formatPointsValue(v any) string {
+	if f, ok := v.(float64); ok {
+		return formatPoints(f)
 	}
-
-	// Fetch usage with cache
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-	usage, err := fetchUsageWithCache(ctx)
-	if err != nil || usage == nil {
-		fmt.Fprintf(out, "Costa: Error fetching usage")
-		return nil
-	}
-
-	// Format: "Costa: X / Y points"
-	pointsStr := "-"
-	if usage.Points.IsValid {
-		pointsStr = formatPoints(usage.Points.Value)
-	}
-	fmt.Fprintf(out, "💫  %s / %s ", pointsStr, usage.TotalPoints)
-	return nil
+	return "-"
 }
 
-func init() {
-	statusCmd.Flags().StringVar(&statusFormat, "format", "", "Output format (json|claude-code)")
+// pointsValue converts an API value into a number, or "-" when it is unknown
+func pointsValue(f api.FlexibleFloat) any {
+	if f.IsValid {
+		return f.Value
+	}
+	return "-"
 }
 
-// FlexibleFloat handles JSON fields that can be either a number or a string like "-"
-type FlexibleFloat struct {
-	Value   float64
-	IsValid bool
-}
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show Costa CLI status",
+	Long: `Display the current login status and usage information.
+
+--format claude-code prints a compact status line. Customize it with
+--format template --template '<go template>' or statusline.template in the
+config file. Templates can use .Points, .Total, .Percent, .ContextLen,
+.CacheAge, .TokenExpiresIn, .Profile and .BaseURL together with the
+color, bar, asciibar, threshold, duration and points helpers.
+
+--warn-at and --fail-at (or warn_at/fail_at under [thresholds] in the config
+file) take a percentage of total points. Crossing them changes the status
+line's icon and color, and makes 'costa status' exit with code 7 (warn) or
+8 (fail) so scripts can stop before the team runs out of points. With a
+threshold set, usage is always fetched fresh, and the command fails when it
+cannot tell how many points are used.
+
+--watch [interval] redraws a live dashboard (default every 5s) until Ctrl-C.`,
+	Example: `  costa status
+  costa status --format claude-code
+  costa status --format template --template '{{.PointsText}}/{{.TotalText}} {{asciibar 10 .Percent}}'
+  costa status --format template --template '{{color (threshold .Percent 80 95 "green" "yellow" "red") (printf "%.0f%%" .Percent)}}'
+  costa status --watch
+  costa status --watch 30s
+  costa status --fail-at 95% || echo "Out of points, skipping agent run"`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if statusRefreshCache {
+			return runUsageRefresh(cmd.Context())
+		}
 
-// UnmarshalJSON implements custom unmarshaling for FlexibleFloat
-func (f *FlexibleFloat) UnmarshalJSON(data []byte) error {
-	// Try to unmarshal as float64 first
-	var num float64
-	if err := json.Unmarshal(data, &num); err == nil {
-		f.Value = num
-		f.IsValid = true
-		return nil
-	}
+		cfg, err := config.Load()
+		if err != nil {
+			return err
+		}
+		thresholds, err := resolveThresholds(statusWarnAt, statusFailAt, cfg)
+		if err != nil {
+			return err
+		}
 
-	// Try to unmarshal as string
-	var str string
-	if err := json.Unmarshal(data, &str); err == nil {
-		// If it's a dash or empty, mark as invalid
-		if str == "-" || str == "" {
-			f.IsValid = false
-			return nil
+		if cmd.Flags().Changed(watchFlagName) {
+			interval, err := parseWatchInterval(statusWatch, args)
+			if err != nil {
+				return err
+			}
+			return runStatusWatch(cmd, interval, thresholds)
 		}
-		// Otherwise it's an unexpected string
-		return fmt.Errorf("unexpected string value for numeric field: %s", str)
-	}
 
-	return fmt.Errorf("cannot unmarshal as number or string")
-}
+		// The status line keeps rendering past a threshold; only its look changes
+		if outputFormat(cmd) == "" && (statusFormat == statusFormatClaudeCode || statusFormat == statusFormatTemplate) {
+			return outputStatusLine(cmd, cfg, thresholds, statusFormat, statusTemplate)
+		}
 
-// UsageInfo represents the usage data from /api/v1/usage
-type UsageInfo struct {
-	TotalPoints string        `json:"total_points"`
-	UpdatedAt   string        `json:"updated_at"`
-	Points      FlexibleFloat `json:"points"`
-	ContextLen  float64       `json:"context_length"`
+		result := buildStatusResult(thresholds)
+		if err := printResult(cmd, result); err != nil {
+			return err
+		}
+		if quotaErr := result.quotaErr(thresholds); quotaErr != nil {
+			quotaErr.resultPrinted = true
+			return quotaErr
+		}
+		return nil
+	},
 }
 
-// fetchUsage fetches usage information from the Costa API
-func fetchUsage(ctx context.Context) (*UsageInfo, error) {
-	debug.Printf("fetchUsage: Starting to fetch usage from API\n")
-
-	// Ensure OAuth token is valid
-	oauthToken, err := auth.EnsureOAuthTokenValid(ctx)
-	if err != nil {
-		debug.Printf("fetchUsage: Failed to get valid OAuth token: %v\n", err)
-		return nil, err
-	}
-
-	// Make request to usage endpoint
-	usageURL := auth.GetBaseURL() + "/api/v1/usage"
-	debug.Printf("fetchUsage: Making request to %s\n", usageURL)
-	req, err := http.NewRequestWithContext(ctx, "GET", usageURL, nil)
-	if err != nil {
-		debug.Printf("fetchUsage: Failed to create request: %v\n", err)
-		return nil, err
+func buildStatusResult(thresholds quotaThresholds) statusResult {
+	result := statusResult{
+		Status:   "ok",
+		LoggedIn: auth.IsLoggedIn(),
 	}
-
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", oauthToken.AccessToken))
-	req.Header.Set("Accept", "application/json")
-
-	client := &http.Client{Timeout: 5 * time.Second}
-	resp, err := client.Do(req)
-	if err != nil {
-		debug.Printf("fetchUsage: HTTP request failed: %v\n", err)
-		return nil, err
+	if !result.LoggedIn {
+		return result
 	}
-	defer func() { _ = resp.Body.Close() }()
-
-	debug.Printf("fetchUsage: Received HTTP %d\n", resp.StatusCode)
+	result.Org = auth.SelectedOrg()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to fetch usage: HTTP %d", resp.StatusCode)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	fetch := fetchUsageWithCache
+	if thresholds.enabled() {
+		// A quota gate must not pass on a stale cached value
+		fetch = currentUsage
 	}
-
-	bodyBytes, err := io.ReadAll(resp.Body)
+	entry, err := fetch(ctx)
 	if err != nil {
-		debug.Printf("fetchUsage: Failed to read response body: %v\n", err)
-		return nil, err
-	}
-
-	debug.Printf("fetchUsage: Response body: %s\n", string(bodyBytes))
+		// Login state is still reported; the usage failure is attached separately
+		body := newErrorBody(err)
+		result.UsageError = &body
+		return result
+	}
+	usage := entry.Usage
+	result.Points = pointsValue(usage.Points)
+	result.TotalPoints = pointsValue(usage.TotalPoints)
+	result.Cache = entry.info()
+	result.Quota = thresholds.evaluate(usage)
+	result.Forecast = forecastFromSamples(usage, loadUsageSamples(), defaultForecastWindow, time.Now())
+	return result
+}
 
-	var usage UsageInfo
-	if err := json.Unmarshal(bodyBytes, &usage); err != nil {
-		debug.Printf("fetchUsage: Failed to unmarshal JSON: %v\n", err)
-		return nil, err
+// quotaErr returns the error that makes 'costa status' exit non-zero: a
+// crossed threshold, or usage that could not be checked against one
+func (r statusResult) quotaErr(thresholds quotaThresholds) *CLIError {
+	if r.Quota != nil || !thresholds.enabled() {
+		return r.Quota.err()
 	}
+	switch {
+	case !r.LoggedIn:
+		return &CLIError{Code: ErrCodeAuthRequired, Message: "cannot check usage thresholds: not logged in", Hint: "Run 'costa login' to authenticate."}
+	case r.UsageError != nil:
+		return &CLIError{Code: r.UsageError.Code, Message: "cannot check usage thresholds: " + r.UsageError.Message, Hint: r.UsageError.Hint}
+	default:
+		return &CLIError{Code: ErrCodeInternal, Message: "cannot check usage thresholds: the percentage of points used is unknown"}
+	}
+}
 
-	debug.Printf("fetchUsage: Parsed usage info: %+v\n", usage)
-
-	return &usage, nil
+func init() {
+	statusCmd.Flags().StringVar(&statusFormat, "format", "", "Output format (json|claude-code|template); json is an alias for --output json")
+	statusCmd.Flags().StringVar(&statusTemplate, "template", "", "Go template for --format template (default: statusline.template from config)")
+	statusCmd.Flags().StringVar(&statusWarnAt, "warn-at", "", "Warn when this percentage of points is used, e.g. 80% (default: thresholds.warn_at from config)")
+	statusCmd.Flags().StringVar(&statusFailAt, "fail-at", "", "Fail when this percentage of points is used, e.g. 95% (default: thresholds.fail_at from config)")
+	statusCmd.Flags().DurationVar(&statusWatch, watchFlagName, defaultWatchInterval, "Redraw a live dashboard every interval until Ctrl-C")
+	statusCmd.Flags().Lookup(watchFlagName).NoOptDefVal = defaultWatchInterval.String()
+	statusCmd.Flags().BoolVar(&statusRefreshCache, refreshCacheFlag, false, "Internal: refresh the usage cache")
+	_ = statusCmd.Flags().MarkHidden(refreshCacheFlag)
 }
 
 // formatPoints formats a points value for display
@@ -229,55 +206,3 @@ func formatPoints(points float64) string {
 	}
 	return fmt.Sprintf("%.1f", points)
 }
-
-// Cache for usage data
-type usageCache struct {
-	data      *UsageInfo
-	timestamp time.Time
-}
-
-var globalUsageCache *usageCache
-
-// fetchUsageWithCache fetches usage with 15-second caching
-func fetchUsageWithCache(ctx context.Context) (*UsageInfo, error) {
-	// Check cache validity (15 seconds)
-	if globalUsageCache != nil && time.Since(globalUsageCache.timestamp) < 15*time.Second {
-		debug.Printf("Cache hit: returning cached usage (age: %v)\n", time.Since(globalUsageCache.timestamp))
-		debug.Printf("Cached data: %+v\n", globalUsageCache.data)
-		return globalUsageCache.data, nil
-	}
-
-	if globalUsageCache != nil {
-		debug.Printf("Cache expired (age: %v), fetching fresh data\n", time.Since(globalUsageCache.timestamp))
-	} else {
-		debug.Printf("No cache available, fetching fresh data\n")
-	}
-
-	// Fetch fresh data
-	usage, err := fetchUsage(ctx)
-	if err != nil {
-		debug.Printf("Error fetching usage: %v\n", err)
-		// Return stale cache if available on error
-		if globalUsageCache != nil {
-			debug.Printf("Returning stale cache due to error\n")
-			return globalUsageCache.data, nil
-		}
-		debug.Printf("No stale cache available, returning error\n")
-		return nil, err
-	}
-
-	debug.Printf("Successfully fetched usage: %+v\n", usage)
-
-	// Only update cache with successful (non-nil) responses
-	if usage != nil {
-		debug.Printf("Updating cache with new data\n")
-		globalUsageCache = &usageCache{
-			data:      usage,
-			timestamp: time.Now(),
-		}
-	} else {
-		debug.Printf("Warning: fetchUsage returned nil usage without error - NOT caching\n")
-	}
-
-	return usage, nil
-}
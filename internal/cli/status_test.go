@@ -0,0 +1,485 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/costa-app/costa-cli/internal/api"
+	"github.com/costa-app/costa-cli/internal/auth"
+	"github.com/costa-app/costa-cli/internal/cache"
+	"github.com/costa-app/costa-cli/internal/config"
+)
+
+// setupStatusServer starts a usage API that counts requests and stubs out background refreshes
+func setupStatusServer(t *testing.T) (requests *atomic.Int32, refreshes *atomic.Int32) {
+	t.Helper()
+
+	requests, refreshes = &atomic.Int32{}, &atomic.Int32{}
+	setupUsageServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/me" {
+			_, _ = w.Write([]byte(`{"email": "dev@example.com", "org": {"id": "1", "name": "Acme"}, "plan": "team"}`))
+			return
+		}
+		requests.Add(1)
+		_, _ = w.Write([]byte(`{"points": 12.5, "total_points": "100", "updated_at": "2025-01-01T00:00:00Z"}`))
+	})
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	original := startUsageRefresh
+	startUsageRefresh = func() { refreshes.Add(1) }
+	t.Cleanup(func() { startUsageRefresh = original })
+
+	return requests, refreshes
+}
+
+func runStatusCommand(t *testing.T, args ...string) string {
+	t.Helper()
+
+	output, err := executeStatusCommand(args...)
+	if err != nil {
+		t.Fatalf("Command failed: %v\noutput: %s", err, output)
+	}
+	return output
+}
+
+func executeStatusCommand(args ...string) (string, error) {
+	return executeStatusCommandWithInput(nil, args...)
+}
+
+func executeStatusCommandWithInput(in io.Reader, args ...string) (string, error) {
+	defer func() {
+		statusFormat, statusTemplate, statusRefreshCache = "", "", false
+		statusWarnAt, statusFailAt = "", ""
+		if f := statusCmd.Flags().Lookup(outputFlagName); f != nil {
+			_ = f.Value.Set("")
+		}
+	}()
+
+	var buf bytes.Buffer
+	testRoot := &cobra.Command{Use: "costa", SilenceErrors: true, SilenceUsage: true}
+	testRoot.PersistentFlags().StringP(outputFlagName, "o", "", outputFlagUsage)
+	testRoot.AddCommand(statusCmd)
+	testRoot.SetOut(&buf)
+	testRoot.SetErr(&buf)
+	testRoot.SetIn(in)
+	testRoot.SetArgs(append([]string{"status"}, args...))
+
+	err := testRoot.Execute()
+	return buf.String(), err
+}
+
+func TestStatus_CacheSharedAcrossInvocations(t *testing.T) {
+	requests, refreshes := setupStatusServer(t)
+
+	first := runStatusCommand(t, "--format", "claude-code")
+	second := runStatusCommand(t, "--format", "claude-code")
+
+	if requests.Load() != 1 {
+		t.Errorf("expected a single API request, got %d", requests.Load())
+	}
+	if refreshes.Load() != 0 {
+		t.Errorf("expected no background refresh for a fresh cache, got %d", refreshes.Load())
+	}
+	if first != second || !strings.Contains(first, "12.5 / 100") {
+		t.Errorf("expected identical cached status lines, got %q and %q", first, second)
+	}
+}
+
+func TestStatus_StaleCacheServedAndRefreshed(t *testing.T) {
+	requests, refreshes := setupStatusServer(t)
+
+	stale := usageCacheEntry{
+		FetchedAt: time.Now().Add(-time.Minute),
+		Usage:     &api.UsageInfo{TotalPoints: api.FlexibleFloat{Value: 100, IsValid: true}, Points: api.FlexibleFloat{Value: 7, IsValid: true}},
+		BaseURL:   auth.GetBaseURL(),
+	}
+	if err := cache.Save(usageCacheName, stale); err != nil {
+		t.Fatalf("Failed to seed cache: %v", err)
+	}
+
+	output := runStatusCommand(t, "-o", "json")
+
+	if requests.Load() != 0 {
+		t.Errorf("expected stale value to be served without an API request, got %d", requests.Load())
+	}
+	if refreshes.Load() != 1 {
+		t.Errorf("expected one background refresh, got %d", refreshes.Load())
+	}
+
+	var result map[string]any
+	if err := json.Unmarshal([]byte(output), &result); err != nil {
+		t.Fatalf("failed to parse JSON output: %v\noutput: %s", err, output)
+	}
+	if result["points"] != 7.0 {
+		t.Errorf("expected cached points 7, got %v", result["points"])
+	}
+	cacheInfo, ok := result["cache"].(map[string]any)
+	if !ok {
+		t.Fatalf("expected cache object, got %v", result["cache"])
+	}
+	if cacheInfo["stale"] != true {
+		t.Errorf("expected stale cache, got %v", cacheInfo["stale"])
+	}
+	if age, _ := cacheInfo["age_seconds"].(float64); age < 59 {
+		t.Errorf("expected age of about a minute, got %v", cacheInfo["age_seconds"])
+	}
+}
+
+func TestStatus_RefreshUpdatesCache(t *testing.T) {
+	requests, _ := setupStatusServer(t)
+
+	runStatusCommand(t, "--"+refreshCacheFlag)
+
+	if requests.Load() != 1 {
+		t.Fatalf("expected refresh to call the API once, got %d", requests.Load())
+	}
+	entry := loadUsageCache()
+	if entry == nil || entry.stale() {
+		t.Fatalf("expected a fresh cache entry, got %+v", entry)
+	}
+	if entry.Usage.Points.Value != 12.5 {
+		t.Errorf("expected cached points 12.5, got %v", entry.Usage.Points.Value)
+	}
+	if identity := auth.LoadIdentity(); identity == nil || identity.Email != "dev@example.com" {
+		t.Errorf("expected refresh to cache the identity, got %+v", identity)
+	}
+}
+
+func TestStatus_Template(t *testing.T) {
+	setupStatusServer(t)
+	t.Setenv("NO_COLOR", "1")
+
+	output := runStatusCommand(t, "--format", "template", "--template",
+		`{{.PointsText}}/{{.TotalText}} {{.Percent}}% {{asciibar 8 .Percent}} {{.Profile}} {{color "red" "x"}}`)
+
+	if output != "12.5/100 12.5% #------- default x" {
+		t.Errorf("unexpected template output: %q", output)
+	}
+}
+
+func TestStatus_TemplateFromConfig(t *testing.T) {
+	setupStatusServer(t)
+	t.Setenv("COSTA_PROFILE", "work")
+
+	configPath, err := config.Path()
+	if err != nil {
+		t.Fatalf("Failed to get config path: %v", err)
+	}
+	if err := os.WriteFile(configPath, []byte("[statusline]\ntemplate = \"{{.Profile}}: {{points .Points}}\"\n"), 0600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	// The configured template also replaces the built-in Claude Code line
+	output := runStatusCommand(t, "--format", "claude-code")
+	if output != "work: 12.5" {
+		t.Errorf("unexpected status line: %q", output)
+	}
+}
+
+func TestStatus_TemplateRequiresTemplate(t *testing.T) {
+	setupStatusServer(t)
+
+	_, err := executeStatusCommand("--format", "template")
+	if ExitCode(err) != ExitUsage {
+		t.Errorf("expected usage error, got %v", err)
+	}
+
+	_, err = executeStatusCommand("--format", "template", "--template", "{{.Points")
+	if ExitCode(err) != ExitUsage {
+		t.Errorf("expected usage error for invalid template, got %v", err)
+	}
+}
+
+func TestStatusLineHelpers(t *testing.T) {
+	if got := progressBar(4, 50, "#", "-"); got != "##--" {
+		t.Errorf("progressBar(4, 50) = %q", got)
+	}
+	if got := progressBar(4, 150, "#", "-"); got != "####" {
+		t.Errorf("progressBar(4, 150) = %q", got)
+	}
+	if got := threshold(85, 80, 95, "ok", "warn", "crit"); got != "warn" {
+		t.Errorf("threshold(85) = %q", got)
+	}
+	if got := threshold(95, 80, 95, "ok", "warn", "crit"); got != "crit" {
+		t.Errorf("threshold(95) = %q", got)
+	}
+
+	durations := map[time.Duration]string{
+		45 * time.Second:             "45s",
+		12 * time.Minute:             "12m",
+		3*time.Hour + 20*time.Minute: "3h20m",
+		52 * time.Hour:               "2d4h",
+	}
+	for d, want := range durations {
+		if got := formatDuration(d); got != want {
+			t.Errorf("formatDuration(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
+
+func TestStatus_ClaudeCodeSessionInput(t *testing.T) {
+	setupStatusServer(t)
+
+	input := `{"session_id":"abc","cwd":"/work/shop/api","model":{"id":"claude-sonnet","display_name":"Sonnet"},` +
+		`"workspace":{"current_dir":"/work/shop/api","project_dir":"/work/shop"},"cost":{"total_cost_usd":1.234}}`
+
+	output, err := executeStatusCommandWithInput(strings.NewReader(input), "--format", "claude-code")
+	if err != nil {
+		t.Fatalf("Command failed: %v", err)
+	}
+	if output != "Sonnet | shop | 💫  12.5 / 100 (+0) | $1.23 " {
+		t.Errorf("unexpected status line: %q", output)
+	}
+
+	output, err = executeStatusCommandWithInput(strings.NewReader(input), "--format", "template",
+		"--template", "{{.SessionID}} {{.ModelID}} {{.ProjectDir}} {{.Session.Cost.TotalCostUSD}}")
+	if err != nil {
+		t.Fatalf("Command failed: %v", err)
+	}
+	if output != "abc claude-sonnet /work/shop 1.234" {
+		t.Errorf("unexpected template output: %q", output)
+	}
+}
+
+func TestSessionPointsDelta(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	start := time.Now()
+
+	if got, ok := sessionPointsDelta("s1", 100, false, start); got != 0 || !ok {
+		t.Errorf("expected 0 for a new session, got %v (%v)", got, ok)
+	}
+	if got, _ := sessionPointsDelta("s1", 112.5, false, start.Add(time.Minute)); got != 12.5 {
+		t.Errorf("expected delta 12.5, got %v", got)
+	}
+	if got, _ := sessionPointsDelta("s2", 112.5, false, start.Add(time.Minute)); got != 0 {
+		t.Errorf("expected sessions to be tracked separately, got %v", got)
+	}
+
+	// A new billing period rebases the session instead of going negative
+	if got, _ := sessionPointsDelta("s1", 3, false, start.Add(2*time.Minute)); got != 0 {
+		t.Errorf("expected 0 after usage reset, got %v", got)
+	}
+	if got, _ := sessionPointsDelta("s1", 5, false, start.Add(3*time.Minute)); got != 2 {
+		t.Errorf("expected delta 2 after usage reset, got %v", got)
+	}
+
+	// Sessions idle past the TTL are garbage-collected on the next write
+	sessionPointsDelta("s3", 5, false, start.Add(sessionStateTTL+time.Hour))
+	var state sessionState
+	if err := cache.Load(sessionStateName, &state); err != nil {
+		t.Fatalf("Failed to load session state: %v", err)
+	}
+	if _, ok := state.Sessions["s2"]; ok {
+		t.Error("expected idle session s2 to be removed")
+	}
+	if _, ok := state.Sessions["s3"]; !ok {
+		t.Error("expected session s3 to be recorded")
+	}
+}
+
+func TestSessionPointsDelta_WaitsForFreshBaseline(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	start := time.Now()
+
+	// The stale total still lacks points spent before the session started
+	if _, ok := sessionPointsDelta("s1", 90, true, start); ok {
+		t.Error("expected no delta while the total is stale")
+	}
+	if got, ok := sessionPointsDelta("s1", 100, false, start.Add(time.Minute)); got != 0 || !ok {
+		t.Errorf("expected the fresh total to become the baseline, got %v (%v)", got, ok)
+	}
+	if got, ok := sessionPointsDelta("s1", 104, true, start.Add(2*time.Minute)); got != 4 || !ok {
+		t.Errorf("expected stale totals to count against a recorded baseline, got %v (%v)", got, ok)
+	}
+}
+
+func TestStatus_SwitchingProfileRefetchesUsage(t *testing.T) {
+	requests := &atomic.Int32{}
+	setupUsageServer(t, func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		points := "12.5"
+		if r.Header.Get(auth.OrgHeader) == "42" {
+			points = "40"
+		}
+		_, _ = w.Write([]byte(`{"points": ` + points + `, "total_points": 100}`))
+	})
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	original := startUsageRefresh
+	startUsageRefresh = func() {}
+	t.Cleanup(func() { startUsageRefresh = original })
+
+	t.Setenv("COSTA_PROFILE", "work")
+	if err := auth.SelectOrg(&auth.Organization{ID: "42", Name: "Work"}); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, step := range []struct {
+		profile string
+		want    float64
+	}{
+		{"", 12.5},
+		{"work", 40},
+		{"work", 40},
+		{"", 12.5},
+	} {
+		t.Setenv("COSTA_PROFILE", step.profile)
+		var result map[string]any
+		if err := json.Unmarshal([]byte(runStatusCommand(t, "-o", "json")), &result); err != nil {
+			t.Fatal(err)
+		}
+		if result["points"] != step.want {
+			t.Errorf("step %d, profile %q: expected %v points, got %v", i, step.profile, step.want, result["points"])
+		}
+	}
+	if requests.Load() != 3 {
+		t.Errorf("expected the cache to be reused only within a profile, got %d requests", requests.Load())
+	}
+
+	// Session baselines and samples from the other organization are not reused
+	if got, _ := sessionPointsDelta("s1", 12.5, false, time.Now()); got != 0 {
+		t.Errorf("expected a new baseline, got %v", got)
+	}
+	t.Setenv("COSTA_PROFILE", "work")
+	if got, ok := sessionPointsDelta("s1", 40, false, time.Now()); got != 0 || !ok {
+		t.Errorf("expected the work organization to get its own baseline, got %v (%v)", got, ok)
+	}
+	if samples := loadUsageSamples(); len(samples) > 0 {
+		t.Errorf("expected no samples for the work organization after the default one was sampled, got %+v", samples)
+	}
+}
+
+func TestStatus_Thresholds(t *testing.T) {
+	setupStatusServer(t)
+
+	// 12.5% used: below warn
+	if _, err := executeStatusCommand("-o", "json", "--warn-at", "20%", "--fail-at", "50%"); err != nil {
+		t.Errorf("expected success below thresholds, got %v", err)
+	}
+
+	output, err := executeStatusCommand("-o", "json", "--warn-at", "10%", "--fail-at", "50%")
+	if ExitCode(err) != ExitQuotaWarning {
+		t.Errorf("expected quota warning exit code, got %d (%v)", ExitCode(err), err)
+	}
+
+	// The status result is still printed, without an error envelope
+	if strings.Count(output, "\n") != 1 {
+		t.Fatalf("expected a single JSON line, got:\n%s", output)
+	}
+	var result map[string]any
+	if err := json.Unmarshal([]byte(output), &result); err != nil {
+		t.Fatalf("failed to parse JSON output: %v\noutput: %s", err, output)
+	}
+	if result["total_points"] != 100.0 {
+		t.Errorf("expected numeric total_points, got %v", result["total_points"])
+	}
+	quota, _ := result["quota"].(map[string]any)
+	if quota["level"] != quotaWarn || quota["percent_used"] != 12.5 {
+		t.Errorf("unexpected quota: %v", quota)
+	}
+
+	_, err = executeStatusCommand("--warn-at", "5", "--fail-at", "12.5%")
+	if ExitCode(err) != ExitQuotaExceeded {
+		t.Errorf("expected quota exceeded exit code, got %d (%v)", ExitCode(err), err)
+	}
+
+	_, err = executeStatusCommand("--fail-at", "lots")
+	if ExitCode(err) != ExitUsage {
+		t.Errorf("expected usage error for invalid threshold, got %v", err)
+	}
+
+	_, err = executeStatusCommand("--warn-at", "90%", "--fail-at", "80%")
+	if ExitCode(err) != ExitUsage {
+		t.Errorf("expected usage error for warn above fail, got %v", err)
+	}
+}
+
+func TestStatus_ThresholdsIgnoreStaleCache(t *testing.T) {
+	requests, refreshes := setupStatusServer(t)
+
+	// The stale value is under the threshold; the fresh one (12.5%) is not
+	stale := usageCacheEntry{
+		FetchedAt: time.Now().Add(-time.Hour),
+		Usage:     &api.UsageInfo{TotalPoints: api.FlexibleFloat{Value: 100, IsValid: true}, Points: api.FlexibleFloat{Value: 1, IsValid: true}},
+		BaseURL:   auth.GetBaseURL(),
+	}
+	if err := cache.Save(usageCacheName, stale); err != nil {
+		t.Fatalf("Failed to seed cache: %v", err)
+	}
+
+	_, err := executeStatusCommand("--fail-at", "10%")
+	if ExitCode(err) != ExitQuotaExceeded {
+		t.Errorf("expected quota exceeded on fresh usage, got %d (%v)", ExitCode(err), err)
+	}
+	if requests.Load() != 1 {
+		t.Errorf("expected a fresh API request, got %d", requests.Load())
+	}
+	if refreshes.Load() != 0 {
+		t.Errorf("expected no background refresh, got %d", refreshes.Load())
+	}
+}
+
+func TestStatus_ThresholdsFailWithoutUsage(t *testing.T) {
+	setupUsageServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	output, err := executeStatusCommand("-o", "json", "--fail-at", "95%")
+	if err == nil || ExitCode(err) == ExitOK {
+		t.Fatalf("expected failure when usage is unavailable, got output %s", output)
+	}
+	var result map[string]any
+	if err := json.Unmarshal([]byte(output), &result); err != nil {
+		t.Fatalf("failed to parse JSON output: %v\noutput: %s", err, output)
+	}
+	if result["usage_error"] == nil {
+		t.Errorf("expected usage_error in output, got %v", result)
+	}
+
+	// Without thresholds, a usage failure is still only reported
+	if _, err := executeStatusCommand("-o", "json"); err != nil {
+		t.Errorf("expected success without thresholds, got %v", err)
+	}
+
+	// Logged out
+	t.Setenv("HOME", t.TempDir())
+	if _, err := executeStatusCommand("--warn-at", "80%"); ExitCode(err) != ExitAuthRequired {
+		t.Errorf("expected auth required when logged out, got %d (%v)", ExitCode(err), err)
+	}
+}
+
+func TestStatus_ThresholdsFromConfig(t *testing.T) {
+	setupStatusServer(t)
+	t.Setenv("NO_COLOR", "1")
+
+	configPath, err := config.Path()
+	if err != nil {
+		t.Fatalf("Failed to get config path: %v", err)
+	}
+	if err := os.WriteFile(configPath, []byte("[thresholds]\nwarn_at = \"10%\"\n"), 0600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	// The status line changes its icon but never fails
+	output := runStatusCommand(t, "--format", "claude-code")
+	if output != "⚠️  12.5 / 100 " {
+		t.Errorf("unexpected status line: %q", output)
+	}
+
+	// Flags take precedence over the config file
+	if _, err := executeStatusCommand("--warn-at", "90%"); err != nil {
+		t.Errorf("expected --warn-at to override config, got %v", err)
+	}
+	if _, err := executeStatusCommand(); ExitCode(err) != ExitQuotaWarning {
+		t.Errorf("expected config warn_at to apply, got %v", err)
+	}
+}
@@ -0,0 +1,212 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/costa-app/costa-cli/internal/auth"
+	"github.com/costa-app/costa-cli/internal/integrations"
+)
+
+const (
+	watchFlagName        = "watch"
+	defaultWatchInterval = 5 * time.Second
+	// integrationCheckInterval limits how often integration status (which may
+	// run external tools) is re-checked while watching
+	integrationCheckInterval = 30 * time.Second
+	// Terminal control sequences used to redraw the dashboard in place
+	ansiAltScreenOn  = "\x1b[?1049h\x1b[?25l"
+	ansiAltScreenOff = "\x1b[?25h\x1b[?1049l"
+	ansiClearScreen  = "\x1b[H\x1b[2J"
+)
+
+// integrationHealth is one row of the dashboard's integrations section
+type integrationHealth struct {
+	Title  string
+	Health string
+}
+
+// watchDashboard holds the state of a 'costa status --watch' session
+type watchDashboard struct {
+	start        time.Time
+	lastChecked  time.Time
+	entry        *usageCacheEntry
+	usageErr     error
+	codingExpiry *time.Time
+	startPoints  *float64
+	integrations []integrationHealth
+	interval     time.Duration
+	thresholds   quotaThresholds
+	loggedIn     bool
+}
+
+// parseWatchInterval resolves --watch, which also accepts its interval as a
+// positional argument ('costa status --watch 10s')
+func parseWatchInterval(flagValue time.Duration, args []string) (time.Duration, error) {
+	interval := flagValue
+	if len(args) > 1 {
+		return 0, &CLIError{Code: ErrCodeUsage, Message: "too many arguments", Hint: "Use 'costa status --watch [interval]'."}
+	}
+	if len(args) == 1 {
+		d, err := time.ParseDuration(args[0])
+		if err != nil {
+			return 0, &CLIError{Code: ErrCodeUsage, Message: fmt.Sprintf("invalid watch interval: %s", args[0]), Hint: "Use a duration such as 5s or 1m.", Err: err}
+		}
+		interval = d
+	}
+	if interval < time.Second {
+		return 0, &CLIError{Code: ErrCodeUsage, Message: "watch interval must be at least 1s"}
+	}
+	return interval, nil
+}
+
+// runStatusWatch redraws the dashboard every interval until interrupted
+func runStatusWatch(cmd *cobra.Command, interval time.Duration, thresholds quotaThresholds) error {
+	if outputFormat(cmd) != "" || statusFormat != "" {
+		return &CLIError{Code: ErrCodeUsage, Message: "--watch cannot be combined with --output or --format"}
+	}
+
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	resized := make(chan os.Signal, 1)
+	notifyResize(resized)
+	defer signal.Stop(resized)
+
+	out := cmd.OutOrStdout()
+	fmt.Fprint(out, ansiAltScreenOn)
+	defer fmt.Fprint(out, ansiAltScreenOff)
+
+	d := &watchDashboard{start: time.Now(), interval: interval, thresholds: thresholds}
+	d.refresh(ctx, time.Now())
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		fmt.Fprint(out, ansiClearScreen)
+		d.render(out, outputWidth(out), time.Now())
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-resized:
+			// Redraw at the new size without refetching
+		case <-ticker.C:
+			d.refresh(ctx, time.Now())
+		}
+	}
+}
+
+// outputWidth returns the terminal width of out, defaulting to 80 columns
+func outputWidth(out io.Writer) int {
+	if f, ok := out.(*os.File); ok {
+		if w := terminalWidth(f); w > 0 {
+			return w
+		}
+	}
+	return 80
+}
+
+// refresh collects login, usage, token and integration state
+func (d *watchDashboard) refresh(ctx context.Context, now time.Time) {
+	d.loggedIn = loadUsageCache() != nil || auth.IsLoggedIn()
+	if !d.loggedIn {
+		return
+	}
+
+	d.codingExpiry = auth.CodingExpiry()
+	d.entry, d.usageErr = currentUsage(ctx)
+	if d.usageErr == nil && d.startPoints == nil && d.entry.Usage.Points.IsValid {
+		points := d.entry.Usage.Points.Value
+		d.startPoints = &points
+	}
+
+	if d.integrations == nil || now.Sub(d.lastChecked) >= integrationCheckInterval {
+		d.integrations = checkIntegrations(ctx, integrations.All()...)
+		d.lastChecked = now
+	}
+}
+
+// checkIntegrations summarizes each integration's user-scope configuration
+func checkIntegrations(ctx context.Context, regs ...integrations.Registration) []integrationHealth {
+	var health []integrationHealth
+	for _, reg := range regs {
+		status, err := reg.New().Status(ctx, integrations.ScopeUser)
+		row := integrationHealth{Title: reg.Title}
+		switch {
+		case err != nil:
+			row.Health = "✗ " + err.Error()
+		case status.IsCosta:
+			row.Health = "✓ Configured for Costa"
+		case status.ConfigExists:
+			row.Health = "⚠ Partially configured"
+		default:
+			row.Health = "✗ Not configured"
+		}
+		health = append(health, row)
+	}
+	return health
+}
+
+// burnRate returns points used per hour since the dashboard started
+func (d *watchDashboard) burnRate(now time.Time) (perHour float64, ok bool) {
+	elapsed := now.Sub(d.start)
+	if d.startPoints == nil || d.entry == nil || !d.entry.Usage.Points.IsValid || elapsed < time.Minute {
+		return 0, false
+	}
+	return (d.entry.Usage.Points.Value - *d.startPoints) / elapsed.Hours(), true
+}
+
+// render draws the dashboard for a terminal of the given width
+func (d *watchDashboard) render(out io.Writer, width int, now time.Time) {
+	header := fmt.Sprintf("Costa status · every %s · Ctrl-C to quit", d.interval)
+	clock := now.Format("15:04:05")
+	fmt.Fprintf(out, "%s%s%s\n\n", header, strings.Repeat(" ", max(width-len([]rune(header))-len(clock), 1)), clock)
+
+	if !d.loggedIn {
+		fmt.Fprintln(out, "Logged in:     no")
+		fmt.Fprintln(out, "\nRun 'costa login' to authenticate.")
+		return
+	}
+	fmt.Fprintln(out, "Logged in:     yes")
+
+	switch {
+	case d.usageErr != nil:
+		fmt.Fprintf(out, "Points:        error: %s\n", classifyError(d.usageErr).Error())
+	case d.entry != nil:
+		usage := d.entry.Usage
+		points := formatPointsValue(pointsValue(usage.Points))
+		total := formatPointsValue(pointsValue(usage.TotalPoints))
+		if quota := d.thresholds.evaluate(usage); quota != nil {
+			barWidth := min(max(width-45, 10), 40)
+			bar := colorize(levelColor(quota.Level), progressBar(barWidth, quota.PercentUsed, "█", "░"))
+			fmt.Fprintf(out, "Points:        %s %s / %s (%s%%)\n", bar, points, total, formatPoints(quota.PercentUsed))
+		} else {
+			fmt.Fprintf(out, "Points:        %s / %s\n", points, total)
+		}
+
+		if rate, ok := d.burnRate(now); ok {
+			fmt.Fprintf(out, "Burn rate:     %s pts/h over %s\n", formatPoints(rate), formatDuration(now.Sub(d.start)))
+		} else {
+			fmt.Fprintln(out, "Burn rate:     collecting…")
+		}
+		fmt.Fprintf(out, "Updated:       %s ago\n", formatDuration(now.Sub(d.entry.FetchedAt)))
+	}
+
+	fmt.Fprintf(out, "Coding token:  %s\n", formatExpiry(d.codingExpiry, now))
+
+	if len(d.integrations) > 0 {
+		fmt.Fprintln(out, "\nIntegrations")
+		for _, row := range d.integrations {
+			fmt.Fprintf(out, "  %-13s %s\n", row.Title, row.Health)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/costa-app/costa-cli/internal/api"
+)
+
+func TestParseWatchInterval(t *testing.T) {
+	if got, err := parseWatchInterval(defaultWatchInterval, nil); err != nil || got != 5*time.Second {
+		t.Errorf("expected default interval, got %v (%v)", got, err)
+	}
+	if got, err := parseWatchInterval(defaultWatchInterval, []string{"30s"}); err != nil || got != 30*time.Second {
+		t.Errorf("expected positional interval, got %v (%v)", got, err)
+	}
+	for _, args := range [][]string{{"soon"}, {"100ms"}, {"1s", "2s"}} {
+		if _, err := parseWatchInterval(defaultWatchInterval, args); ExitCode(err) != ExitUsage {
+			t.Errorf("expected usage error for %v, got %v", args, err)
+		}
+	}
+}
+
+func TestWatchDashboardRender(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	now := start.Add(30 * time.Minute)
+	expiry := now.Add(50 * time.Hour)
+	warnAt := 40.0
+	startPoints := 300.0
+
+	d := &watchDashboard{
+		start:    start,
+		interval: 5 * time.Second,
+		loggedIn: true,
+		entry: &usageCacheEntry{
+			FetchedAt: now.Add(-3 * time.Second),
+			Usage: &api.UsageInfo{
+				Points:      api.FlexibleFloat{Value: 450, IsValid: true},
+				TotalPoints: api.FlexibleFloat{Value: 1000, IsValid: true},
+			},
+		},
+		startPoints:  &startPoints,
+		codingExpiry: &expiry,
+		thresholds:   quotaThresholds{WarnAt: &warnAt},
+		integrations: []integrationHealth{{Title: "Claude Code", Health: "✓ Configured for Costa"}},
+	}
+
+	var buf bytes.Buffer
+	d.render(&buf, 80, now)
+	output := buf.String()
+
+	for _, want := range []string{
+		"Logged in:     yes",
+		"450 / 1000 (45%)",
+		"Burn rate:     300 pts/h over 30m",
+		"Updated:       3s ago",
+		"Coding token:  expires in 2d2h",
+		"Claude Code   ✓ Configured for Costa",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %q in dashboard:\n%s", want, output)
+		}
+	}
+
+	// The header spans the terminal width so the clock is right-aligned
+	header := strings.SplitN(output, "\n", 2)[0]
+	if len([]rune(header)) != 80 || !strings.HasSuffix(header, "12:30:00") {
+		t.Errorf("unexpected header %q", header)
+	}
+}
@@ -0,0 +1,338 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/costa-app/costa-cli/internal/auth"
+	"github.com/costa-app/costa-cli/internal/config"
+	"github.com/costa-app/costa-cli/internal/debug"
+	"github.com/costa-app/costa-cli/internal/integrations/claudecode"
+)
+
+// Status line formats accepted by 'costa status --format'
+const (
+	statusFormatClaudeCode = "claude-code"
+	statusFormatTemplate   = "template"
+)
+
+// defaultStatusLineTemplate is the built-in Claude Code status line. Session
+// details are only shown when Claude Code passed them on stdin.
+const defaultStatusLineTemplate = `{{if .Model}}{{.Model}} | {{end}}{{if .ProjectName}}{{.ProjectName}} | {{end}}` +
+	`{{if not .LoggedIn}}Costa: Not logged in{{else if .Error}}Costa: Error fetching usage{{else}}{{levelIcon .Level}}  {{color (levelColor .Level) .PointsText}} / {{.TotalText}} {{if .HasSessionPoints}}(+{{points .SessionPoints}}) {{end}}{{end}}` +
+	`{{if .HasSession}}| ${{printf "%.2f" .SessionCost}} {{end}}`
+
+const (
+	// maxStatusLineInput bounds how much stdin is read for status line input
+	maxStatusLineInput = 1 << 20
+	// statusLineInputTimeout guards against a stdin pipe that is never closed
+	statusLineInputTimeout = 500 * time.Millisecond
+)
+
+// ansiColors are the color names accepted by the status line "color" helper
+var ansiColors = map[string]string{
+	"bold":    "1",
+	"dim":     "2",
+	"red":     "31",
+	"green":   "32",
+	"yellow":  "33",
+	"blue":    "34",
+	"magenta": "35",
+	"cyan":    "36",
+	"gray":    "90",
+}
+
+// statusLineData is the data available to status line templates
+type statusLineData struct {
+	// Session is the raw Claude Code status line input, if any
+	Session *claudecode.StatusLineInput
+	// WarnAt and FailAt are the configured thresholds in percent; nil if unset
+	WarnAt *float64
+	FailAt *float64
+	// Error is set when usage could not be fetched
+	Error      string
+	PointsText string // Points formatted for display, or "-" when unknown
+	TotalText  string // TotalPoints formatted for display, or "-" when unknown
+	// Level is the quota level for Percent: ok, warn or fail
+	Level   string
+	Profile string
+	BaseURL string
+	// Email, Org and Plan describe the cached 'costa whoami' identity, if any
+	Email string
+	Org   string
+	Plan  string
+	// Session details from Claude Code; empty when not run as its status line
+	SessionID      string
+	Model          string
+	ModelID        string
+	Cwd            string
+	ProjectDir     string
+	ProjectName    string
+	TranscriptPath string
+	Points         float64
+	Total          float64
+	Percent        float64 // Percentage of Total used, 0-100
+	ContextLen     float64
+	// SessionCost is the session cost in USD as reported by Claude Code
+	SessionCost float64
+	// SessionPoints is the points used since this Claude Code session was first seen
+	SessionPoints float64
+	// BurnRate is the rolling points-per-hour burn rate from local samples
+	BurnRate float64
+	CacheAge time.Duration
+	// ExhaustsIn is the projected time until the allowance runs out; see HasForecast
+	ExhaustsIn time.Duration
+	// TokenExpiresIn is the time until the OAuth token expires; zero if unknown
+	TokenExpiresIn time.Duration
+	LoggedIn       bool
+	HasSession     bool
+	// HasSessionPoints is set when SessionPoints could be computed
+	HasSessionPoints bool
+	HasPoints        bool
+	CacheStale       bool
+	// HasForecast is set when enough samples exist for BurnRate
+	HasForecast bool
+	// ExhaustsBeforeReset is set when ExhaustsIn falls before the period end
+	ExhaustsBeforeReset bool
+}
+
+// outputStatusLine renders the compact status line used by Claude Code.
+// The template comes from --template, then statusline.template in the config file.
+func outputStatusLine(cmd *cobra.Command, cfg *config.Config, thresholds quotaThresholds, format, explicit string) error {
+	text := explicit
+	if text == "" {
+		text = cfg.StatusLine.Template
+	}
+	if text == "" {
+		if format == statusFormatTemplate {
+			return &CLIError{
+				Code:    ErrCodeUsage,
+				Message: "--format template requires a template",
+				Hint:    "Pass --template '<go template>' or set statusline.template in the config file.",
+			}
+		}
+		text = defaultStatusLineTemplate
+	}
+
+	tmpl, err := template.New("statusline").Funcs(statusLineFuncs()).Parse(text)
+	if err != nil {
+		if explicit == "" {
+			return &config.Error{Desc: "invalid statusline.template", Err: err}
+		}
+		return &CLIError{Code: ErrCodeUsage, Message: fmt.Sprintf("invalid --template: %v", err), Err: err}
+	}
+
+	data := buildStatusLineData(cfg, thresholds)
+	if input := readStatusLineInput(cmd.InOrStdin()); input != nil {
+		data.setSession(input)
+	}
+	return tmpl.Execute(cmd.OutOrStdout(), data)
+}
+
+// readStatusLineInput parses the JSON Claude Code pipes to status line commands.
+// Interactive terminals and empty or unrelated input yield nil.
+func readStatusLineInput(in io.Reader) *claudecode.StatusLineInput {
+	if f, ok := in.(*os.File); ok {
+		info, err := f.Stat()
+		if err != nil || info.Mode()&os.ModeCharDevice != 0 {
+			return nil
+		}
+	}
+
+	read := make(chan []byte, 1)
+	go func() {
+		data, _ := io.ReadAll(io.LimitReader(in, maxStatusLineInput))
+		read <- data
+	}()
+
+	select {
+	case data := <-read:
+		return claudecode.ParseStatusLineInput(data)
+	case <-time.After(statusLineInputTimeout):
+		debug.Printf("Timed out reading status line input\n")
+		return nil
+	}
+}
+
+func (d *statusLineData) setSession(input *claudecode.StatusLineInput) {
+	d.Session = input
+	d.HasSession = true
+	d.SessionID = input.SessionID
+	d.Model = input.ModelName()
+	d.ModelID = input.Model.ID
+	d.Cwd = input.Cwd
+	d.ProjectDir = input.ProjectDir()
+	d.ProjectName = input.ProjectName()
+	d.TranscriptPath = input.TranscriptPath
+	d.SessionCost = input.Cost.TotalCostUSD
+
+	if d.HasPoints && d.Error == "" {
+		d.SessionPoints, d.HasSessionPoints = sessionPointsDelta(input.SessionID, d.Points, d.CacheStale, time.Now())
+	}
+}
+
+// buildStatusLineData gathers usage, cache and token state for a status line render
+func buildStatusLineData(cfg *config.Config, thresholds quotaThresholds) statusLineData {
+	data := statusLineData{
+		Profile:    cfg.ActiveProfile(),
+		BaseURL:    auth.GetBaseURL(),
+		PointsText: "-",
+		TotalText:  "-",
+		Level:      quotaOK,
+		WarnAt:     thresholds.WarnAt,
+		FailAt:     thresholds.FailAt,
+	}
+
+	// A cached value means we were logged in when it was fetched (logout clears
+	// the cache), so the status line can skip the keyring entirely
+	if loadUsageCache() == nil && !auth.IsLoggedIn() {
+		return data
+	}
+	data.LoggedIn = true
+
+	if identity := auth.LoadIdentity(); identity != nil {
+		data.Email = identity.Email
+		data.Plan = identity.Plan
+		if identity.Org != nil {
+			data.Org = identity.Org.Name
+		}
+	}
+	if org := auth.SelectedOrg(); org != nil {
+		data.Org = org.Name
+	}
+
+	if expiry := auth.OAuthExpiry(); expiry != nil {
+		data.TokenExpiresIn = max(time.Until(*expiry).Truncate(time.Second), 0)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	entry, err := fetchUsageWithCache(ctx)
+	if err != nil {
+		data.Error = err.Error()
+		return data
+	}
+
+	usage := entry.Usage
+	data.CacheAge = entry.age().Truncate(time.Second)
+	data.CacheStale = entry.stale()
+	data.ContextLen = usage.ContextLen
+	if usage.Points.IsValid {
+		data.HasPoints = true
+		data.Points = usage.Points.Value
+		data.PointsText = formatPoints(usage.Points.Value)
+	}
+	if usage.TotalPoints.IsValid {
+		data.Total = usage.TotalPoints.Value
+		data.TotalText = formatPoints(usage.TotalPoints.Value)
+	}
+	if quota := thresholds.evaluate(usage); quota != nil {
+		data.Percent = quota.PercentUsed
+		data.Level = quota.Level
+	}
+	if forecast := forecastFromSamples(usage, loadUsageSamples(), defaultForecastWindow, time.Now()); forecast != nil {
+		data.HasForecast = true
+		data.BurnRate = forecast.BurnRatePerHour
+		data.ExhaustsBeforeReset = forecast.ExhaustsBeforeReset
+		if forecast.ExhaustsAt != nil {
+			data.ExhaustsIn = max(time.Until(*forecast.ExhaustsAt).Truncate(time.Second), 0)
+		}
+	}
+	return data
+}
+
+// statusLineFuncs returns the helpers available to status line templates
+func statusLineFuncs() template.FuncMap {
+	funcs := templateFuncs()
+	funcs["color"] = colorize
+	funcs["bar"] = func(width int, percent float64) string {
+		return progressBar(width, percent, "█", "░")
+	}
+	funcs["asciibar"] = func(width int, percent float64) string {
+		return progressBar(width, percent, "#", "-")
+	}
+	funcs["threshold"] = threshold
+	funcs["levelColor"] = levelColor
+	funcs["levelIcon"] = levelIcon
+	funcs["duration"] = formatDuration
+	funcs["points"] = formatPoints
+	return funcs
+}
+
+// colorize wraps text in an ANSI color; NO_COLOR disables it
+func colorize(name string, text any) string {
+	s := fmt.Sprint(text)
+	code, ok := ansiColors[name]
+	if !ok || os.Getenv("NO_COLOR") != "" {
+		return s
+	}
+	return "\x1b[" + code + "m" + s + "\x1b[0m"
+}
+
+// progressBar renders a fixed-width bar filled to percent (0-100)
+func progressBar(width int, percent float64, full, empty string) string {
+	if width <= 0 {
+		return ""
+	}
+	filled := int(math.Round(math.Max(0, math.Min(percent, 100)) / 100 * float64(width)))
+	return strings.Repeat(full, filled) + strings.Repeat(empty, width-filled)
+}
+
+// threshold picks ok, warn or crit depending on which limit value has reached,
+// e.g. {{color (threshold .Percent 80 95 "green" "yellow" "red") .PointsText}}
+func threshold(value, warnAt, critAt float64, ok, warn, crit string) string {
+	switch {
+	case value >= critAt:
+		return crit
+	case value >= warnAt:
+		return warn
+	default:
+		return ok
+	}
+}
+
+// levelColor returns the color name for a quota level ("" leaves text uncolored)
+func levelColor(level string) string {
+	switch level {
+	case quotaFail:
+		return "red"
+	case quotaWarn:
+		return "yellow"
+	default:
+		return ""
+	}
+}
+
+// levelIcon returns the status line icon for a quota level
+func levelIcon(level string) string {
+	switch level {
+	case quotaFail:
+		return "🛑"
+	case quotaWarn:
+		return "⚠️"
+	default:
+		return "💫"
+	}
+}
+
+// formatDuration renders a duration compactly, e.g. 45s, 12m, 3h20m or 2d4h
+func formatDuration(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh%dm", int(d.Hours()), int(d.Minutes())%60)
+	default:
+		return fmt.Sprintf("%dd%dh", int(d.Hours())/24, int(d.Hours())%24)
+	}
+}
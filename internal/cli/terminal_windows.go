@@ -0,0 +1,22 @@
+//go:build windows
+
+package cli
+
+import (
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// terminalWidth returns the width of the console attached to f, or 0 if unknown
+func terminalWidth(f *os.File) int {
+	var info windows.ConsoleScreenBufferInfo
+	if err := windows.GetConsoleScreenBufferInfo(windows.Handle(f.Fd()), &info); err != nil {
+		return 0
+	}
+	return int(info.Window.Right - info.Window.Left + 1)
+}
+
+// notifyResize is a no-op on Windows, which has no resize signal; the
+// dashboard picks up the new width on its next redraw
+func notifyResize(ch chan<- os.Signal) {}
@@ -1,8 +1,9 @@
 package cli
 
 import (
-	"encoding/json"
 	"fmt"
+	"io"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -16,6 +17,24 @@ var (
 	tokenFormat       string
 )
 
+// tokenInfo describes a single token in 'costa token' output
+type tokenInfo struct {
+	ExpiresAt    *time.Time `json:"expires_at,omitempty"`
+	TokenType    string     `json:"token_type"`
+	AccessToken  string     `json:"access_token"`
+	RefreshToken string     `json:"refresh_token,omitempty"`
+}
+
+// tokenResult is the output of 'costa token'
+type tokenResult struct {
+	Coding   *tokenInfo `json:"coding,omitempty"`
+	OAuth    *tokenInfo `json:"oauth,omitempty"`
+	Status   string     `json:"status"`
+	LoggedIn bool       `json:"logged_in"`
+	// hasOAuth is true when an OAuth token exists even if it is not shown
+	hasOAuth bool
+}
+
 var tokenCmd = &cobra.Command{
 	Use:   "token",
 	Short: "Display authentication token",
@@ -23,16 +42,7 @@ var tokenCmd = &cobra.Command{
 	RunE: func(cmd *cobra.Command, args []string) error {
 		// Check if logged in
 		if !auth.IsLoggedIn() {
-			if tokenFormat == "json" {
-				output := map[string]interface{}{
-					"logged_in": false,
-				}
-				data, _ := json.Marshal(output)
-				fmt.Fprintln(cmd.OutOrStdout(), string(data))
-				return nil
-			}
-			fmt.Fprintln(cmd.OutOrStdout(), "Not logged in")
-			return nil
+			return printResult(cmd, tokenResult{Status: "not_logged_in"})
 		}
 
 		// Load token
@@ -52,112 +62,67 @@ var tokenCmd = &cobra.Command{
 			}
 		}
 
-		// JSON output
-		if tokenFormat == "json" {
-			return outputJSON(cmd, token)
-		}
-
-		// Human-readable output
-		return outputHuman(cmd, token)
+		return printResult(cmd, newTokenResult(token))
 	},
 }
 
-func outputJSON(cmd *cobra.Command, token *auth.Token) error {
-	output := map[string]interface{}{
-		"logged_in": true,
-	}
-
-	// Add coding token
-	if token.Coding != nil {
-		codingData := map[string]interface{}{
-			"token_type": token.Coding.TokenType,
-		}
-		if tokenRaw {
-			codingData["access_token"] = token.Coding.AccessToken
-			if token.Coding.RefreshToken != "" {
-				codingData["refresh_token"] = token.Coding.RefreshToken
-			}
-		} else {
-			codingData["access_token"] = redactToken(token.Coding.AccessToken)
-		}
-		if token.Coding.ExpiresAt != nil {
-			codingData["expires_at"] = token.Coding.ExpiresAt.Format("2006-01-02T15:04:05Z07:00")
-		}
-		output["coding"] = codingData
+func newTokenResult(token *auth.Token) tokenResult {
+	result := tokenResult{
+		Status:   "ok",
+		LoggedIn: true,
+		Coding:   newTokenInfo(token.Coding),
+		hasOAuth: token.OAuth != nil,
 	}
 
 	// Add OAuth token only if --include-oauth and COSTA_DEBUG=1
-	if tokenIncludeOAuth && debug.IsEnabled() && token.OAuth != nil {
-		oauthData := map[string]interface{}{
-			"token_type": token.OAuth.TokenType,
-		}
-		if tokenRaw {
-			oauthData["access_token"] = token.OAuth.AccessToken
-			if token.OAuth.RefreshToken != "" {
-				oauthData["refresh_token"] = token.OAuth.RefreshToken
-			}
-		} else {
-			oauthData["access_token"] = redactToken(token.OAuth.AccessToken)
-		}
-		if token.OAuth.ExpiresAt != nil {
-			oauthData["expires_at"] = token.OAuth.ExpiresAt.Format("2006-01-02T15:04:05Z07:00")
-		}
-		output["oauth"] = oauthData
+	if tokenIncludeOAuth && debug.IsEnabled() {
+		result.OAuth = newTokenInfo(token.OAuth)
 	}
 
-	data, err := json.Marshal(output)
-	if err != nil {
-		return err
-	}
+	return result
+}
 
-	fmt.Fprintln(cmd.OutOrStdout(), string(data))
-	return nil
+func newTokenInfo(td *auth.TokenData) *tokenInfo {
+	if td == nil {
+		return nil
+	}
+	info := &tokenInfo{TokenType: td.TokenType}
+	if tokenRaw {
+		info.AccessToken = td.AccessToken
+		info.RefreshToken = td.RefreshToken
+	} else {
+		info.AccessToken = redactToken(td.AccessToken)
+	}
+	if td.ExpiresAt != nil {
+		expiresAt := td.ExpiresAt.Truncate(time.Second)
+		info.ExpiresAt = &expiresAt
+	}
+	return info
 }
 
-func outputHuman(cmd *cobra.Command, token *auth.Token) error {
-	out := cmd.OutOrStdout()
+func (r tokenResult) printHuman(out io.Writer) error {
+	if !r.LoggedIn {
+		fmt.Fprintln(out, "Not logged in")
+		return nil
+	}
 
 	fmt.Fprintln(out, "Logged in: yes")
 	fmt.Fprintln(out, "")
 
 	// Show coding token
-	if token.Coding != nil {
+	if r.Coding != nil {
 		fmt.Fprintln(out, "Coding Token:")
-		fmt.Fprintf(out, "  Type: %s\n", token.Coding.TokenType)
-		if tokenRaw {
-			fmt.Fprintf(out, "  Access Token: %s\n", token.Coding.AccessToken)
-			if token.Coding.RefreshToken != "" {
-				fmt.Fprintf(out, "  Refresh Token: %s\n", token.Coding.RefreshToken)
-			}
-		} else {
-			fmt.Fprintf(out, "  Access Token: %s\n", redactToken(token.Coding.AccessToken))
-		}
-		if token.Coding.ExpiresAt != nil {
-			fmt.Fprintf(out, "  Expires: %s\n", token.Coding.ExpiresAt.Format("2006-01-02 15:04:05 MST"))
-		}
-		fmt.Fprintln(out, "")
+		printTokenInfo(out, r.Coding)
 	}
 
 	// Show OAuth token only if --include-oauth and COSTA_DEBUG=1
-	if tokenIncludeOAuth && debug.IsEnabled() && token.OAuth != nil {
+	if r.OAuth != nil {
 		fmt.Fprintln(out, "OAuth Token (debug):")
-		fmt.Fprintf(out, "  Type: %s\n", token.OAuth.TokenType)
-		if tokenRaw {
-			fmt.Fprintf(out, "  Access Token: %s\n", token.OAuth.AccessToken)
-			if token.OAuth.RefreshToken != "" {
-				fmt.Fprintf(out, "  Refresh Token: %s\n", token.OAuth.RefreshToken)
-			}
-		} else {
-			fmt.Fprintf(out, "  Access Token: %s\n", redactToken(token.OAuth.AccessToken))
-		}
-		if token.OAuth.ExpiresAt != nil {
-			fmt.Fprintf(out, "  Expires: %s\n", token.OAuth.ExpiresAt.Format("2006-01-02 15:04:05 MST"))
-		}
-		fmt.Fprintln(out, "")
+		printTokenInfo(out, r.OAuth)
 	}
 
 	// Show hint if no coding token yet
-	if token.Coding == nil && token.OAuth != nil {
+	if r.Coding == nil && r.hasOAuth {
 		fmt.Fprintln(out, "Note: No coding token found.")
 	}
 
@@ -168,6 +133,18 @@ func outputHuman(cmd *cobra.Command, token *auth.Token) error {
 	return nil
 }
 
+func printTokenInfo(out io.Writer, info *tokenInfo) {
+	fmt.Fprintf(out, "  Type: %s\n", info.TokenType)
+	fmt.Fprintf(out, "  Access Token: %s\n", info.AccessToken)
+	if info.RefreshToken != "" {
+		fmt.Fprintf(out, "  Refresh Token: %s\n", info.RefreshToken)
+	}
+	if info.ExpiresAt != nil {
+		fmt.Fprintf(out, "  Expires: %s\n", info.ExpiresAt.Format("2006-01-02 15:04:05 MST"))
+	}
+	fmt.Fprintln(out, "")
+}
+
 // redactToken redacts a token to show only first 6 and last 4 characters
 func redactToken(token string) string {
 	if len(token) <= 10 {
@@ -178,7 +155,7 @@ func redactToken(token string) string {
 
 func init() {
 	tokenCmd.Flags().BoolVar(&tokenRaw, "raw", false, "Show full token (use with caution)")
-	tokenCmd.Flags().StringVar(&tokenFormat, "format", "", "Output format (json)")
+	tokenCmd.Flags().StringVar(&tokenFormat, "format", "", "Output format (json); alias for --output json")
 
 	// Only show --include-oauth flag if COSTA_DEBUG is enabled
 	oauthFlag := tokenCmd.Flags().VarPF(
@@ -0,0 +1,250 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/costa-app/costa-cli/internal/api"
+)
+
+var (
+	usageSince   string
+	usageFrom    string
+	usageTo      string
+	usageGroupBy string
+)
+
+// sparkTicks are the bar characters used for the daily burn sparkline
+var sparkTicks = []rune("▁▂▃▄▅▆▇█")
+
+// usageRow is one group in 'costa usage' output
+type usageRow struct {
+	Key      string  `json:"key"`
+	Points   float64 `json:"points"`
+	Share    float64 `json:"share_percent"`
+	Requests int     `json:"requests"`
+}
+
+// usageResult is the output of 'costa usage'
+type usageResult struct {
+	Status      string     `json:"status"`
+	From        string     `json:"from"`
+	To          string     `json:"to"`
+	GroupBy     string     `json:"group_by"`
+	Entries     []usageRow `json:"entries"`
+	TotalPoints float64    `json:"total_points"`
+	// daily holds points per day for the sparkline (human output only)
+	daily []float64
+}
+
+var usageCmd = &cobra.Command{
+	Use:   "usage",
+	Short: "Show detailed usage history",
+	Long: `Show points usage over a time range, grouped by day, model, integration or project.
+
+Use -o csv or -o json to export usage for reporting.`,
+	Example: `  costa usage
+  costa usage --since 7d --group-by model
+  costa usage --from 2025-01-01 --to 2025-01-31 -o csv`,
+	RunE: runUsage,
+}
+
+func init() {
+	usageCmd.Flags().StringVar(&usageSince, "since", "30d", "Time range ending now (e.g. 7d, 4w, 48h)")
+	usageCmd.Flags().StringVar(&usageFrom, "from", "", "Start date (YYYY-MM-DD); overrides --since")
+	usageCmd.Flags().StringVar(&usageTo, "to", "", "End date (YYYY-MM-DD, default today)")
+	usageCmd.Flags().StringVar(&usageGroupBy, "group-by", api.GroupByDay, "Group by day|model|integration|project")
+}
+
+func runUsage(cmd *cobra.Command, args []string) error {
+	query, err := buildUsageQuery(time.Now())
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), 2*api.DefaultTimeout)
+	defer cancel()
+
+	history, err := api.GetUsageHistory(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	result := newUsageResult(query, history)
+
+	// The sparkline always shows daily burn, so fetch it separately for other groupings
+	if outputFormat(cmd) == "" {
+		if query.GroupBy == api.GroupByDay {
+			result.daily = dailySeries(query, history.Entries)
+		} else if daily, err := api.GetUsageHistory(ctx, api.UsageHistoryQuery{From: query.From, To: query.To, GroupBy: api.GroupByDay}); err == nil {
+			result.daily = dailySeries(query, daily.Entries)
+		}
+	}
+
+	return printResult(cmd, result)
+}
+
+// buildUsageQuery resolves the --since/--from/--to/--group-by flags
+func buildUsageQuery(now time.Time) (api.UsageHistoryQuery, error) {
+	query := api.UsageHistoryQuery{GroupBy: usageGroupBy}
+
+	switch usageGroupBy {
+	case api.GroupByDay, api.GroupByModel, api.GroupByIntegration, api.GroupByProject:
+	default:
+		return query, &CLIError{
+			Code:    ErrCodeUsage,
+			Message: fmt.Sprintf("invalid --group-by: %s", usageGroupBy),
+			Hint:    "Use one of: day, model, integration, project.",
+		}
+	}
+
+	query.To = now
+	if usageTo != "" {
+		to, err := time.ParseInLocation("2006-01-02", usageTo, now.Location())
+		if err != nil {
+			return query, &CLIError{Code: ErrCodeUsage, Message: fmt.Sprintf("invalid --to date: %s", usageTo), Hint: "Use YYYY-MM-DD."}
+		}
+		query.To = to
+	}
+
+	if usageFrom != "" {
+		from, err := time.ParseInLocation("2006-01-02", usageFrom, now.Location())
+		if err != nil {
+			return query, &CLIError{Code: ErrCodeUsage, Message: fmt.Sprintf("invalid --from date: %s", usageFrom), Hint: "Use YYYY-MM-DD."}
+		}
+		query.From = from
+	} else {
+		since, err := parseSince(usageSince)
+		if err != nil {
+			return query, &CLIError{Code: ErrCodeUsage, Message: fmt.Sprintf("invalid --since: %s", usageSince), Hint: "Use e.g. 7d, 4w or 48h.", Err: err}
+		}
+		query.From = query.To.Add(-since)
+	}
+
+	if query.From.After(query.To) {
+		return query, &CLIError{Code: ErrCodeUsage, Message: "--from must not be after --to"}
+	}
+
+	return query, nil
+}
+
+// parseSince parses a duration with optional day (d) and week (w) units
+func parseSince(s string) (time.Duration, error) {
+	for suffix, unit := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if n, ok := strings.CutSuffix(s, suffix); ok {
+			count, err := strconv.Atoi(n)
+			if err != nil || count < 0 {
+				return 0, fmt.Errorf("invalid duration %q", s)
+			}
+			return time.Duration(count) * unit, nil
+		}
+	}
+	return time.ParseDuration(s)
+}
+
+func newUsageResult(query api.UsageHistoryQuery, history *api.UsageHistory) usageResult {
+	result := usageResult{
+		Status:      "ok",
+		From:        query.From.Format("2006-01-02"),
+		To:          query.To.Format("2006-01-02"),
+		GroupBy:     query.GroupBy,
+		TotalPoints: history.TotalPoints,
+		Entries:     []usageRow{},
+	}
+
+	// Fall back to summing entries if the API did not return a total
+	if result.TotalPoints == 0 {
+		for _, e := range history.Entries {
+			result.TotalPoints += e.Points
+		}
+	}
+
+	for _, e := range history.Entries {
+		row := usageRow{Key: e.Key, Points: e.Points, Requests: e.Requests}
+		if result.TotalPoints > 0 {
+			row.Share = math.Round(e.Points/result.TotalPoints*1000) / 10
+		}
+		result.Entries = append(result.Entries, row)
+	}
+
+	return result
+}
+
+// dailySeries returns points per calendar day in the query range, filling gaps with zero
+func dailySeries(query api.UsageHistoryQuery, entries []api.UsageEntry) []float64 {
+	byDay := make(map[string]float64, len(entries))
+	for _, e := range entries {
+		byDay[e.Key] += e.Points
+	}
+
+	var series []float64
+	day := time.Date(query.From.Year(), query.From.Month(), query.From.Day(), 0, 0, 0, 0, query.From.Location())
+	for !day.After(query.To) {
+		series = append(series, byDay[day.Format("2006-01-02")])
+		day = day.AddDate(0, 0, 1)
+	}
+	return series
+}
+
+// sparkline renders values as a single line of block characters
+func sparkline(values []float64) string {
+	if len(values) == 0 {
+		return ""
+	}
+
+	lo, hi := values[0], values[0]
+	for _, v := range values {
+		lo = math.Min(lo, v)
+		hi = math.Max(hi, v)
+	}
+
+	var b strings.Builder
+	for _, v := range values {
+		idx := 0
+		if hi > lo {
+			idx = int((v - lo) / (hi - lo) * float64(len(sparkTicks)-1))
+		}
+		b.WriteRune(sparkTicks[idx])
+	}
+	return b.String()
+}
+
+func (r usageResult) table() ([]string, [][]string) {
+	headers := []string{strings.ToUpper(r.GroupBy), "POINTS", "REQUESTS", "SHARE"}
+	var rows [][]string
+	for _, e := range r.Entries {
+		rows = append(rows, []string{
+			e.Key,
+			formatPoints(e.Points),
+			strconv.Itoa(e.Requests),
+			fmt.Sprintf("%.1f%%", e.Share),
+		})
+	}
+	return headers, rows
+}
+
+func (r usageResult) printHuman(out io.Writer) error {
+	fmt.Fprintf(out, "Usage %s → %s (by %s)\n\n", r.From, r.To, r.GroupBy)
+
+	if len(r.Entries) == 0 {
+		fmt.Fprintln(out, "No usage in this period.")
+		return nil
+	}
+
+	if err := writeTable(out, r); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "\nTotal: %s points\n", formatPoints(r.TotalPoints))
+	if line := sparkline(r.daily); line != "" {
+		fmt.Fprintf(out, "Daily: %s\n", line)
+	}
+	return nil
+}
@@ -0,0 +1,183 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/costa-app/costa-cli/internal/api"
+	"github.com/costa-app/costa-cli/internal/auth"
+	"github.com/costa-app/costa-cli/internal/cache"
+	"github.com/costa-app/costa-cli/internal/debug"
+)
+
+const (
+	usageCacheName = "usage"
+	// usageCacheTTL is how long cached usage is served without a refresh
+	usageCacheTTL = 15 * time.Second
+	// usageRefreshLockTimeout bounds how long a crashed refresh can block others
+	usageRefreshLockTimeout = 30 * time.Second
+	refreshCacheFlag        = "refresh-cache"
+)
+
+// usageCacheEntry is the on-disk usage cache shared by all costa processes
+type usageCacheEntry struct {
+	FetchedAt time.Time      `json:"fetched_at"`
+	Usage     *api.UsageInfo `json:"usage"`
+	BaseURL   string         `json:"base_url"`
+	// OrgID is the organization the usage was fetched for
+	OrgID string `json:"org_id,omitempty"`
+}
+
+// usageCacheInfo describes the cache entry a status result was served from
+type usageCacheInfo struct {
+	FetchedAt  time.Time `json:"fetched_at"`
+	AgeSeconds int       `json:"age_seconds"`
+	Stale      bool      `json:"stale"`
+}
+
+func (e *usageCacheEntry) age() time.Duration {
+	return time.Since(e.FetchedAt)
+}
+
+func (e *usageCacheEntry) stale() bool {
+	return e.age() >= usageCacheTTL
+}
+
+func (e *usageCacheEntry) info() *usageCacheInfo {
+	return &usageCacheInfo{
+		FetchedAt:  e.FetchedAt.Truncate(time.Second),
+		AgeSeconds: int(e.age().Seconds()),
+		Stale:      e.stale(),
+	}
+}
+
+// startUsageRefresh refreshes the cache in the background; replaced in tests
+var startUsageRefresh = spawnUsageRefresh
+
+// fetchUsageWithCache returns cached usage immediately when present. A stale
+// entry is still returned, and a detached process refreshes it for the next call.
+func fetchUsageWithCache(ctx context.Context) (*usageCacheEntry, error) {
+	if entry := loadUsageCache(); entry != nil {
+		if entry.stale() {
+			debug.Printf("Cache stale (age: %v), refreshing in background\n", entry.age())
+			startUsageRefresh()
+		} else {
+			debug.Printf("Cache hit: returning cached usage (age: %v)\n", entry.age())
+		}
+		return entry, nil
+	}
+
+	debug.Printf("No cache available, fetching fresh data\n")
+	return refreshUsageCache(ctx)
+}
+
+// loadUsageCache returns the cached usage, or nil if there is none for the
+// current base URL and selected organization
+func loadUsageCache() *usageCacheEntry {
+	var entry usageCacheEntry
+	if err := cache.Load(usageCacheName, &entry); err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			debug.Printf("Ignoring unreadable usage cache: %v\n", err)
+		}
+		return nil
+	}
+	if entry.Usage == nil || entry.BaseURL != auth.GetBaseURL() || entry.OrgID != auth.SelectedOrgID() {
+		return nil
+	}
+	return &entry
+}
+
+// currentUsage returns fresh usage, refreshing the cache in-process instead
+// of in a background process when it is stale
+func currentUsage(ctx context.Context) (*usageCacheEntry, error) {
+	if entry := loadUsageCache(); entry != nil && !entry.stale() {
+		return entry, nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, api.DefaultTimeout)
+	defer cancel()
+	return refreshUsageCache(ctx)
+}
+
+// refreshUsageCache fetches usage from the API and stores it in the cache
+func refreshUsageCache(ctx context.Context) (*usageCacheEntry, error) {
+	usage, err := api.GetUsage(ctx)
+	if err != nil {
+		debug.Printf("Error fetching usage: %v\n", err)
+		return nil, err
+	}
+	if usage == nil {
+		return nil, errors.New("empty usage response")
+	}
+
+	entry := &usageCacheEntry{
+		FetchedAt: time.Now(),
+		Usage:     usage,
+		BaseURL:   auth.GetBaseURL(),
+		OrgID:     auth.SelectedOrgID(),
+	}
+	if err := cache.Save(usageCacheName, entry); err != nil {
+		// A read-only cache dir only costs us the next round-trip
+		debug.Printf("Failed to write usage cache: %v\n", err)
+	}
+	recordUsageSample(usage, entry.FetchedAt)
+	return entry, nil
+}
+
+// spawnUsageRefresh starts a detached 'costa status --refresh-cache' unless one is already running
+func spawnUsageRefresh() {
+	if cache.IsLocked(usageCacheName, usageRefreshLockTimeout) {
+		debug.Printf("Usage refresh already in progress\n")
+		return
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		debug.Printf("Failed to get executable path: %v\n", err)
+		return
+	}
+
+	// #nosec G204 -- executable is from os.Executable(), which is our own binary
+	bgCmd := exec.Command(executable, "status", "--"+refreshCacheFlag)
+	bgCmd.Stdout = nil
+	bgCmd.Stderr = nil
+	bgCmd.Stdin = nil
+	configureProcessDetachment(bgCmd)
+
+	if err := bgCmd.Start(); err != nil {
+		debug.Printf("Failed to start background refresh: %v\n", err)
+		return
+	}
+	_ = bgCmd.Process.Release()
+}
+
+// runUsageRefresh is the body of the background refresh process. The lock
+// ensures concurrent status line renders trigger at most one API request.
+func runUsageRefresh(ctx context.Context) error {
+	unlock, ok, err := cache.TryLock(usageCacheName, usageRefreshLockTimeout)
+	if err != nil || !ok {
+		return err
+	}
+	defer unlock()
+
+	// Another process may have refreshed while we were starting
+	if entry := loadUsageCache(); entry != nil && !entry.stale() {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, api.DefaultTimeout)
+	defer cancel()
+	if _, err = refreshUsageCache(ctx); err != nil {
+		return err
+	}
+
+	// Fetch the identity once so status lines can show which account is active
+	if auth.LoadIdentity() == nil {
+		if _, err := api.GetIdentity(ctx); err != nil {
+			debug.Printf("Failed to fetch identity: %v\n", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,161 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/costa-app/costa-cli/internal/api"
+)
+
+// setupUsageServer starts a stand-in Costa API and a logged-in HOME pointing at it
+func setupUsageServer(t *testing.T, handler http.HandlerFunc) {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+	t.Setenv("COSTA_BASE_URL", server.URL)
+
+	// File fallback token so no keyring is needed
+	configDir := filepath.Join(tmpDir, ".config", "costa")
+	if err := os.MkdirAll(configDir, 0700); err != nil {
+		t.Fatalf("Failed to create config dir: %v", err)
+	}
+	expiresAt := time.Now().Add(time.Hour).Format(time.RFC3339)
+	token := `{"oauth":{"access_token":"test-oauth","token_type":"Bearer","expires_at":"` + expiresAt + `"}}`
+	if err := os.WriteFile(filepath.Join(configDir, "token.json"), []byte(token), 0600); err != nil {
+		t.Fatalf("Failed to write token: %v", err)
+	}
+}
+
+func runUsageCommand(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+
+	defer func() {
+		usageSince, usageFrom, usageTo, usageGroupBy = "30d", "", "", api.GroupByDay
+		for _, cmd := range []*cobra.Command{usageCmd, usageForecastCmd} {
+			if f := cmd.Flags().Lookup(outputFlagName); f != nil {
+				_ = f.Value.Set("")
+			}
+		}
+	}()
+
+	var buf bytes.Buffer
+	testRoot := &cobra.Command{Use: "costa"}
+	testRoot.PersistentFlags().StringP(outputFlagName, "o", "", outputFlagUsage)
+	testRoot.AddCommand(usageCmd)
+	testRoot.SetOut(&buf)
+	testRoot.SetErr(&buf)
+	testRoot.SetArgs(append([]string{"usage"}, args...))
+
+	err := testRoot.Execute()
+	return buf.String(), err
+}
+
+func TestUsageCommand_GroupByModelCSV(t *testing.T) {
+	var gotQuery string
+	setupUsageServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/usage/history" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer test-oauth" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		gotQuery = r.URL.RawQuery
+		_, _ = w.Write([]byte(`{"group_by":"model","total_points":100,"entries":[{"key":"costa/auto","points":75,"requests":30},{"key":"costa/fast","points":25,"requests":10}]}`))
+	})
+
+	output, err := runUsageCommand(t, "--from", "2025-01-01", "--to", "2025-01-31", "--group-by", "model", "-o", "csv")
+	if err != nil {
+		t.Fatalf("usage command failed: %v", err)
+	}
+
+	if !strings.Contains(gotQuery, "from=2025-01-01") || !strings.Contains(gotQuery, "group_by=model") {
+		t.Errorf("unexpected query: %s", gotQuery)
+	}
+
+	records, err := csv.NewReader(strings.NewReader(output)).ReadAll()
+	if err != nil {
+		t.Fatalf("failed to parse CSV: %v\noutput: %s", err, output)
+	}
+	if len(records) != 3 {
+		t.Fatalf("expected header + 2 rows, got %d:\n%s", len(records), output)
+	}
+	if records[0][0] != "MODEL" {
+		t.Errorf("expected MODEL header, got %v", records[0])
+	}
+	if records[1][0] != "costa/auto" || records[1][1] != "75" || records[1][3] != "75.0%" {
+		t.Errorf("unexpected first row: %v", records[1])
+	}
+}
+
+func TestUsageCommand_DailyJSON(t *testing.T) {
+	setupUsageServer(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"group_by":"day","entries":[{"key":"2025-01-01","points":4},{"key":"2025-01-03","points":6}]}`))
+	})
+
+	output, err := runUsageCommand(t, "--from", "2025-01-01", "--to", "2025-01-03", "-o", "json")
+	if err != nil {
+		t.Fatalf("usage command failed: %v", err)
+	}
+
+	var result usageResult
+	if err := json.Unmarshal([]byte(strings.TrimSpace(output)), &result); err != nil {
+		t.Fatalf("failed to parse JSON output: %v\noutput: %s", err, output)
+	}
+	if result.TotalPoints != 10 {
+		t.Errorf("expected total_points summed from entries (10), got %v", result.TotalPoints)
+	}
+	if len(result.Entries) != 2 {
+		t.Errorf("expected 2 entries, got %d", len(result.Entries))
+	}
+}
+
+func TestUsageCommand_HumanSparkline(t *testing.T) {
+	setupUsageServer(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"group_by":"day","entries":[{"key":"2025-01-01","points":4},{"key":"2025-01-02","points":8}]}`))
+	})
+
+	output, err := runUsageCommand(t, "--from", "2025-01-01", "--to", "2025-01-03")
+	if err != nil {
+		t.Fatalf("usage command failed: %v", err)
+	}
+	if !strings.Contains(output, "Daily: ▄█▁") {
+		t.Errorf("expected sparkline with zero-filled last day, got:\n%s", output)
+	}
+}
+
+func TestUsageCommand_InvalidGroupBy(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	_, err := runUsageCommand(t, "--group-by", "planet")
+	if ExitCode(err) != ExitUsage {
+		t.Errorf("expected usage error, got: %v", err)
+	}
+}
+
+func TestSparkline(t *testing.T) {
+	if got := sparkline([]float64{0, 7}); got != "▁█" {
+		t.Errorf("sparkline = %q, want %q", got, "▁█")
+	}
+	if got := sparkline([]float64{3, 3}); got != "▁▁" {
+		t.Errorf("flat sparkline = %q, want %q", got, "▁▁")
+	}
+	if got := sparkline(nil); got != "" {
+		t.Errorf("empty sparkline = %q, want empty", got)
+	}
+}
@@ -1,8 +1,8 @@
 package cli
 
 import (
-	"encoding/json"
 	"fmt"
+	"io"
 
 	"github.com/spf13/cobra"
 
@@ -14,36 +14,34 @@ var (
 	versionFormat string
 )
 
+// versionResult is the output of 'costa version'
+type versionResult struct {
+	Status  string `json:"status"`
+	Version string `json:"version"`
+	Commit  string `json:"commit"`
+	Date    string `json:"date"`
+}
+
+func (r versionResult) printHuman(w io.Writer) error {
+	// Print full version by default (matches tests)
+	fmt.Fprintln(w, version.GetFull())
+	return nil
+}
+
 var versionCmd = &cobra.Command{
 	Use:   "version",
 	Short: "Print the version number of costa",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		if versionFormat == "json" {
-			return outputVersionJSON(cmd)
-		}
-		// Print full version by default (matches tests)
-		fmt.Fprintln(cmd.OutOrStdout(), version.GetFull())
-		return nil
+		return printResult(cmd, versionResult{
+			Status:  "ok",
+			Version: version.Get(),
+			Commit:  version.Commit,
+			Date:    version.Date,
+		})
 	},
 }
 
-func outputVersionJSON(cmd *cobra.Command) error {
-	output := map[string]string{
-		"version": version.Get(),
-		"commit":  version.Commit,
-		"date":    version.Date,
-	}
-
-	data, err := json.Marshal(output)
-	if err != nil {
-		return err
-	}
-
-	fmt.Fprintln(cmd.OutOrStdout(), string(data))
-	return nil
-}
-
 func init() {
 	versionCmd.Flags().BoolVarP(&longVersion, "long", "l", false, "Show full version with commit and build date")
-	versionCmd.Flags().StringVar(&versionFormat, "format", "", "Output format (json)")
+	versionCmd.Flags().StringVar(&versionFormat, "format", "", "Output format (json); alias for --output json")
 }
@@ -0,0 +1,102 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/costa-app/costa-cli/internal/api"
+	"github.com/costa-app/costa-cli/internal/auth"
+)
+
+// whoamiResult is the output of 'costa whoami'
+type whoamiResult struct {
+	Org             *auth.Organization `json:"org,omitempty"`
+	OAuthExpiresAt  *time.Time         `json:"oauth_expires_at,omitempty"`
+	CodingExpiresAt *time.Time         `json:"coding_expires_at,omitempty"`
+	Status          string             `json:"status"`
+	Email           string             `json:"email"`
+	Name            string             `json:"name,omitempty"`
+	Plan            string             `json:"plan,omitempty"`
+	Scopes          []string           `json:"scopes"`
+}
+
+var whoamiCmd = &cobra.Command{
+	Use:   "whoami",
+	Short: "Show the account the CLI is logged in as",
+	Long: `Show the email, organization, plan, granted scopes and token expiries of
+the logged-in account. The result is cached so the status line and
+'costa setup status' can show which identity is active.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, cancel := context.WithTimeout(cmd.Context(), api.DefaultTimeout)
+		defer cancel()
+
+		identity, err := api.GetIdentity(ctx)
+		if err != nil {
+			return err
+		}
+		return printResult(cmd, newWhoamiResult(identity))
+	},
+}
+
+func newWhoamiResult(identity *auth.Identity) whoamiResult {
+	result := whoamiResult{
+		Status:          "ok",
+		Email:           identity.Email,
+		Name:            identity.Name,
+		Org:             identity.Org,
+		Plan:            identity.Plan,
+		Scopes:          identity.Scopes,
+		OAuthExpiresAt:  auth.OAuthExpiry(),
+		CodingExpiresAt: auth.CodingExpiry(),
+	}
+	if result.Scopes == nil {
+		result.Scopes = []string{}
+	}
+	return result
+}
+
+func (r whoamiResult) printHuman(out io.Writer) error {
+	account := r.Email
+	if r.Name != "" {
+		account = fmt.Sprintf("%s <%s>", r.Name, r.Email)
+	}
+	fmt.Fprintf(out, "Account:       %s\n", account)
+	if r.Org != nil {
+		fmt.Fprintf(out, "Organization:  %s\n", formatOrg(r.Org))
+	}
+	if r.Plan != "" {
+		fmt.Fprintf(out, "Plan:          %s\n", r.Plan)
+	}
+	if len(r.Scopes) > 0 {
+		fmt.Fprintf(out, "Scopes:        %s\n", strings.Join(r.Scopes, ", "))
+	}
+	fmt.Fprintf(out, "OAuth token:   %s\n", formatExpiry(r.OAuthExpiresAt, time.Now()))
+	fmt.Fprintf(out, "Coding token:  %s\n", formatExpiry(r.CodingExpiresAt, time.Now()))
+	return nil
+}
+
+// formatOrg renders an organization as "Name (slug)"
+func formatOrg(org *auth.Organization) string {
+	if org.Slug != "" && org.Slug != org.Name {
+		return fmt.Sprintf("%s (%s)", org.Name, org.Slug)
+	}
+	return org.Name
+}
+
+// formatExpiry describes a token expiry relative to now
+func formatExpiry(expiresAt *time.Time, now time.Time) string {
+	switch {
+	case expiresAt == nil:
+		return "no expiry"
+	case expiresAt.After(now):
+		return fmt.Sprintf("expires in %s (%s)", formatDuration(expiresAt.Sub(now)), expiresAt.Local().Format("2006-01-02 15:04"))
+	default:
+		return "expired"
+	}
+}
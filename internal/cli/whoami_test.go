@@ -0,0 +1,80 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/costa-app/costa-cli/internal/auth"
+)
+
+func runWhoamiCommand(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+
+	defer func() {
+		if f := whoamiCmd.Flags().Lookup(outputFlagName); f != nil {
+			_ = f.Value.Set("")
+		}
+	}()
+
+	var buf bytes.Buffer
+	testRoot := &cobra.Command{Use: "costa", SilenceErrors: true, SilenceUsage: true}
+	testRoot.PersistentFlags().StringP(outputFlagName, "o", "", outputFlagUsage)
+	testRoot.AddCommand(whoamiCmd)
+	testRoot.SetOut(&buf)
+	testRoot.SetErr(&buf)
+	testRoot.SetArgs(append([]string{"whoami"}, args...))
+
+	err := testRoot.Execute()
+	return buf.String(), err
+}
+
+func TestWhoami(t *testing.T) {
+	setupUsageServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/me" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(`{"email": "dev@example.com", "name": "Dev", "org": {"id": "42", "name": "Acme", "slug": "acme"}, "plan": "Team", "scopes": ["usage", "api_tokens:read"]}`))
+	})
+
+	output, err := runWhoamiCommand(t)
+	if err != nil {
+		t.Fatalf("Command failed: %v", err)
+	}
+	for _, want := range []string{"Dev <dev@example.com>", "Acme (acme)", "Team", "usage, api_tokens:read", "OAuth token:   expires in"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %q in output:\n%s", want, output)
+		}
+	}
+
+	output, err = runWhoamiCommand(t, "-o", "json")
+	if err != nil {
+		t.Fatalf("Command failed: %v", err)
+	}
+	var result map[string]any
+	if err := json.Unmarshal([]byte(output), &result); err != nil {
+		t.Fatalf("failed to parse JSON output: %v\noutput: %s", err, output)
+	}
+	if result["email"] != "dev@example.com" || result["plan"] != "Team" || result["oauth_expires_at"] == nil {
+		t.Errorf("unexpected whoami JSON: %v", result)
+	}
+
+	// The identity is cached for the status line and setup status
+	identity := auth.LoadIdentity()
+	if identity == nil || identity.Org == nil || identity.Org.ID != "42" {
+		t.Errorf("expected identity to be cached, got %+v", identity)
+	}
+}
+
+func TestWhoami_NotLoggedIn(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	if _, err := runWhoamiCommand(t); ExitCode(err) != ExitAuthRequired {
+		t.Errorf("expected auth required exit code, got %d (%v)", ExitCode(err), err)
+	}
+}
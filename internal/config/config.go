@@ -0,0 +1,145 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/pelletier/go-toml/v2"
+)
+
+// DefaultProfile is the profile used when none is configured
+const DefaultProfile = "default"
+
+// Config represents the user-editable settings in ~/.config/costa/config.toml
+type Config struct {
+	// Profile names the active account profile (overridden by COSTA_PROFILE)
+	Profile string `toml:"profile,omitempty"`
+	// CAFile is a PEM bundle of extra root CAs trusted for outbound HTTPS
+	CAFile string `toml:"ca_file,omitempty"`
+	// ClientCert and ClientKey are a PEM certificate/key pair used for mTLS
+	ClientCert string `toml:"client_cert,omitempty"`
+	ClientKey  string `toml:"client_key,omitempty"`
+	// StatusLine customizes 'costa status --format claude-code|template'
+	StatusLine StatusLineConfig `toml:"statusline,omitempty"`
+	// Thresholds are the default --warn-at/--fail-at for 'costa status'
+	Thresholds ThresholdsConfig `toml:"thresholds,omitempty"`
+	// Backups is the retention policy for config backups made by 'costa setup'
+	Backups BackupsConfig `toml:"backups,omitempty"`
+}
+
+// BackupsConfig is the [backups] section of the config file. Zero values
+// disable the corresponding limit, so backups are kept forever by default.
+type BackupsConfig struct {
+	// Keep is the number of newest backups kept per config file
+	Keep int `toml:"keep,omitempty"`
+	// MaxAgeDays removes older backups, beyond the newest Keep
+	MaxAgeDays int `toml:"max_age_days,omitempty"`
+}
+
+// ThresholdsConfig is the [thresholds] section of the config file.
+// Values are percentages of total points, e.g. "80%".
+type ThresholdsConfig struct {
+	WarnAt string `toml:"warn_at,omitempty"`
+	FailAt string `toml:"fail_at,omitempty"`
+}
+
+// StatusLineConfig is the [statusline] section of the config file
+type StatusLineConfig struct {
+	// Template is a Go template used instead of the built-in status line
+	Template string `toml:"template,omitempty"`
+}
+
+// ActiveProfile returns the configured profile name, or DefaultProfile
+func (c *Config) ActiveProfile() string {
+	if c.Profile == "" {
+		return DefaultProfile
+	}
+	return c.Profile
+}
+
+// Error reports an unreadable or invalid user configuration value
+type Error struct {
+	Err  error
+	Desc string
+}
+
+func (e *Error) Error() string {
+	if e.Err == nil {
+		return e.Desc
+	}
+	return e.Desc + ": " + e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Dir returns the costa config directory path
+func Dir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".config", "costa"), nil
+}
+
+// Path returns the path to the config file
+func Path() (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "config.toml"), nil
+}
+
+// Load reads the config file and applies environment overrides.
+// A missing config file is not an error and yields an empty config.
+func Load() (*Config, error) {
+	cfg := &Config{}
+
+	path, err := Path()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, &Error{Desc: fmt.Sprintf("failed to read config %s", path), Err: err}
+	}
+	if err == nil {
+		if err := toml.Unmarshal(data, cfg); err != nil {
+			return nil, &Error{Desc: fmt.Sprintf("failed to parse config %s", path), Err: err}
+		}
+	}
+
+	// Environment variables take precedence over the config file
+	if v := os.Getenv("COSTA_CA_BUNDLE"); v != "" {
+		cfg.CAFile = v
+	}
+	if v := os.Getenv("COSTA_CLIENT_CERT"); v != "" {
+		cfg.ClientCert = v
+	}
+	if v := os.Getenv("COSTA_CLIENT_KEY"); v != "" {
+		cfg.ClientKey = v
+	}
+	if v := os.Getenv("COSTA_PROFILE"); v != "" {
+		cfg.Profile = v
+	}
+
+	return cfg, nil
+}
+
+// CurrentProfile returns the active profile name. COSTA_PROFILE wins without
+// reading the config file; otherwise a config file that cannot be read is an
+// error rather than a silent switch to the default profile.
+func CurrentProfile() (string, error) {
+	if v := os.Getenv("COSTA_PROFILE"); v != "" {
+		return v, nil
+	}
+	cfg, err := Load()
+	if err != nil {
+		return "", err
+	}
+	return cfg.ActiveProfile(), nil
+}
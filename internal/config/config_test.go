@@ -0,0 +1,55 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeConfig(t *testing.T, content string) {
+	t.Helper()
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	dir := filepath.Join(home, ".config", "costa")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "config.toml"), []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCurrentProfile(t *testing.T) {
+	writeConfig(t, "profile = \"work\"\n")
+	t.Setenv("COSTA_PROFILE", "")
+
+	profile, err := CurrentProfile()
+	if err != nil || profile != "work" {
+		t.Errorf("expected work from the config file, got %q (%v)", profile, err)
+	}
+
+	t.Setenv("COSTA_PROFILE", "ci")
+	profile, err = CurrentProfile()
+	if err != nil || profile != "ci" {
+		t.Errorf("expected COSTA_PROFILE to win, got %q (%v)", profile, err)
+	}
+}
+
+func TestCurrentProfile_BrokenConfig(t *testing.T) {
+	writeConfig(t, "profile = \n")
+
+	t.Setenv("COSTA_PROFILE", "work")
+	profile, err := CurrentProfile()
+	if err != nil || profile != "work" {
+		t.Errorf("expected COSTA_PROFILE without reading the config, got %q (%v)", profile, err)
+	}
+
+	t.Setenv("COSTA_PROFILE", "")
+	profile, err = CurrentProfile()
+	var cfgErr *Error
+	if !errors.As(err, &cfgErr) {
+		t.Errorf("expected a config error instead of falling back to %q, got %v", profile, err)
+	}
+}
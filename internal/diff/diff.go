@@ -0,0 +1,139 @@
+// Package diff computes line diffs of small text files such as tool configs.
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Op is the kind of change a diff line represents
+type Op int
+
+const (
+	Equal Op = iota
+	Delete
+	Insert
+)
+
+// Line is one line of a diff
+type Line struct {
+	Text string
+	Op   Op
+}
+
+// Lines diffs a and b line by line using their longest common subsequence
+func Lines(a, b []string) []Line {
+	// lcs[i][j] is the LCS length of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var lines []Line
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			lines = append(lines, Line{Text: a[i], Op: Equal})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			lines = append(lines, Line{Text: a[i], Op: Delete})
+			i++
+		default:
+			lines = append(lines, Line{Text: b[j], Op: Insert})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		lines = append(lines, Line{Text: a[i], Op: Delete})
+	}
+	for ; j < len(b); j++ {
+		lines = append(lines, Line{Text: b[j], Op: Insert})
+	}
+	return lines
+}
+
+// SplitLines splits text into lines without their line endings
+func SplitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+// Unified renders the changes from a to b as a unified diff with context
+// lines around each change. It returns "" when a and b have the same lines.
+func Unified(fromName, toName, a, b string, context int) string {
+	lines := Lines(SplitLines(a), SplitLines(b))
+
+	var changes []int
+	for i, line := range lines {
+		if line.Op != Equal {
+			changes = append(changes, i)
+		}
+	}
+	if len(changes) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", fromName, toName)
+
+	// Line numbers in a and b at the start of each diff line
+	aPos, bPos := make([]int, len(lines)), make([]int, len(lines))
+	for i, ai, bi := 0, 0, 0; i < len(lines); i++ {
+		aPos[i], bPos[i] = ai, bi
+		if lines[i].Op != Insert {
+			ai++
+		}
+		if lines[i].Op != Delete {
+			bi++
+		}
+	}
+
+	for k := 0; k < len(changes); {
+		// Merge changes whose context would overlap into one hunk
+		last := k
+		for last+1 < len(changes) && changes[last+1]-changes[last] <= 2*context {
+			last++
+		}
+		start := max(changes[k]-context, 0)
+		end := min(changes[last]+context+1, len(lines))
+		k = last + 1
+
+		var aLen, bLen int
+		for _, line := range lines[start:end] {
+			if line.Op != Insert {
+				aLen++
+			}
+			if line.Op != Delete {
+				bLen++
+			}
+		}
+		fmt.Fprintf(&sb, "@@ -%s +%s @@\n", hunkRange(aPos[start], aLen), hunkRange(bPos[start], bLen))
+		for _, line := range lines[start:end] {
+			sb.WriteString(linePrefix[line.Op] + line.Text + "\n")
+		}
+	}
+	return sb.String()
+}
+
+var linePrefix = map[Op]string{Equal: " ", Delete: "-", Insert: "+"}
+
+// hunkRange formats a hunk's start line and length; an empty range names the line before it
+func hunkRange(pos, n int) string {
+	if n == 0 {
+		return fmt.Sprintf("%d,0", pos)
+	}
+	return fmt.Sprintf("%d,%d", pos+1, n)
+}
@@ -0,0 +1,38 @@
+package diff
+
+import "testing"
+
+func TestUnified(t *testing.T) {
+	a := "one\ntwo\nthree\nfour\nfive\nsix\nseven\neight\nnine\n"
+	b := "one\ntwo\nTHREE\nfour\nfive\nsix\nseven\neight\nnine\nten\n"
+
+	got := Unified("a", "b", a, b, 1)
+	want := `--- a
++++ b
+@@ -2,3 +2,3 @@
+ two
+-three
++THREE
+ four
+@@ -9,1 +9,2 @@
+ nine
++ten
+`
+	if got != want {
+		t.Errorf("Unified() =\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestUnified_Equal(t *testing.T) {
+	if got := Unified("a", "b", "x\ny\n", "x\ny", 3); got != "" {
+		t.Errorf("expected no diff, got:\n%s", got)
+	}
+}
+
+func TestUnified_FromEmpty(t *testing.T) {
+	got := Unified("a", "b", "", "x\n", 3)
+	want := "--- a\n+++ b\n@@ -0,0 +1,1 @@\n+x\n"
+	if got != want {
+		t.Errorf("Unified() =\n%q\nwant:\n%q", got, want)
+	}
+}
@@ -0,0 +1,99 @@
+package httpclient
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"golang.org/x/oauth2"
+
+	"github.com/costa-app/costa-cli/internal/config"
+	"github.com/costa-app/costa-cli/internal/debug"
+)
+
+// New returns an HTTP client for outbound Costa API calls.
+// It honors HTTPS_PROXY/HTTP_PROXY/NO_PROXY, the configured CA bundle
+// (COSTA_CA_BUNDLE or ca_file) and an optional client certificate for mTLS.
+func New(timeout time.Duration) (*http.Client, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	transport, err := newTransport(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.Client{
+		Transport: transport,
+		Timeout:   timeout,
+	}, nil
+}
+
+// Context returns a context carrying the configured HTTP client so that
+// oauth2 token exchanges and refreshes use the same proxy and TLS settings
+func Context(ctx context.Context) (context.Context, error) {
+	client, err := New(30 * time.Second)
+	if err != nil {
+		return nil, err
+	}
+	return context.WithValue(ctx, oauth2.HTTPClient, client), nil
+}
+
+func newTransport(cfg *config.Config) (*http.Transport, error) {
+	base, ok := http.DefaultTransport.(*http.Transport)
+	if !ok {
+		return nil, fmt.Errorf("unexpected default transport type %T", http.DefaultTransport)
+	}
+	transport := base.Clone()
+	transport.Proxy = http.ProxyFromEnvironment
+
+	tlsConfig, err := newTLSConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	transport.TLSClientConfig = tlsConfig
+
+	return transport, nil
+}
+
+func newTLSConfig(cfg *config.Config) (*tls.Config, error) {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if cfg.CAFile != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			debug.Printf("System cert pool unavailable, using CA bundle only: %v\n", err)
+			pool = x509.NewCertPool()
+		}
+
+		pem, err := os.ReadFile(cfg.CAFile)
+		if err != nil {
+			return nil, &config.Error{Desc: fmt.Sprintf("failed to read CA bundle %s", cfg.CAFile), Err: err}
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, &config.Error{Desc: fmt.Sprintf("no certificates found in CA bundle %s", cfg.CAFile)}
+		}
+		debug.Printf("Loaded CA bundle from %s\n", cfg.CAFile)
+		tlsConfig.RootCAs = pool
+	}
+
+	if cfg.ClientCert != "" || cfg.ClientKey != "" {
+		if cfg.ClientCert == "" || cfg.ClientKey == "" {
+			return nil, &config.Error{Desc: "client certificate and key must both be set"}
+		}
+		cert, err := tls.LoadX509KeyPair(cfg.ClientCert, cfg.ClientKey)
+		if err != nil {
+			return nil, &config.Error{Desc: "failed to load client certificate", Err: err}
+		}
+		debug.Printf("Loaded client certificate from %s\n", cfg.ClientCert)
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
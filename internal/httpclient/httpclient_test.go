@@ -0,0 +1,81 @@
+package httpclient
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNew_TrustsCABundle(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	// Without the bundle the self-signed server must be rejected
+	client, err := New(5 * time.Second)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	if resp, err := client.Get(server.URL); err == nil {
+		_ = resp.Body.Close()
+		t.Fatal("Expected TLS verification error without CA bundle")
+	}
+
+	// Write the server certificate as a CA bundle
+	bundlePath := filepath.Join(t.TempDir(), "ca.pem")
+	pemData := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(bundlePath, pemData, 0600); err != nil {
+		t.Fatalf("Failed to write CA bundle: %v", err)
+	}
+	t.Setenv("COSTA_CA_BUNDLE", bundlePath)
+
+	client, err = New(5 * time.Second)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Expected request to succeed with CA bundle, got: %v", err)
+	}
+	_ = resp.Body.Close()
+}
+
+func TestNew_CAFileFromConfig(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("COSTA_CA_BUNDLE", "")
+
+	configDir := filepath.Join(home, ".config", "costa")
+	if err := os.MkdirAll(configDir, 0700); err != nil {
+		t.Fatalf("Failed to create config dir: %v", err)
+	}
+	missing := filepath.Join(home, "missing.pem")
+	if err := os.WriteFile(filepath.Join(configDir, "config.toml"), []byte("ca_file = '"+missing+"'\n"), 0600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	_, err := New(5 * time.Second)
+	if err == nil {
+		t.Fatal("Expected error for missing CA bundle")
+	}
+	if !strings.Contains(err.Error(), missing) {
+		t.Errorf("Expected error to mention %s, got: %v", missing, err)
+	}
+}
+
+func TestNew_ClientCertRequiresKey(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("COSTA_CLIENT_CERT", "/tmp/cert.pem")
+
+	if _, err := New(5 * time.Second); err == nil {
+		t.Fatal("Expected error when client key is missing")
+	}
+}
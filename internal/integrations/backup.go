@@ -0,0 +1,306 @@
+package integrations
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/costa-app/costa-cli/internal/config"
+	"github.com/costa-app/costa-cli/internal/debug"
+	"github.com/costa-app/costa-cli/internal/safefile"
+	"github.com/costa-app/costa-cli/pkg/version"
+)
+
+// manifestSuffix is appended to a backup's path to name its manifest
+const manifestSuffix = ".manifest.json"
+
+// ErrBackupNotFound is returned by FindBackup for an unknown backup ID
+var ErrBackupNotFound = errors.New("backup not found")
+
+// Manifest describes a backup. It is stored next to the backup as
+// <backup>.manifest.json.
+type Manifest struct {
+	Created    time.Time `json:"created"`
+	ID         string    `json:"id"`
+	App        string    `json:"app"`
+	Scope      Scope     `json:"scope,omitempty"`
+	SourcePath string    `json:"source_path"`
+	BackupPath string    `json:"backup_path"`
+	CLIVersion string    `json:"cli_version"`
+}
+
+// BackupsDir returns the directory holding each app's backups
+func BackupsDir() (string, error) {
+	configDir, err := config.Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "backups"), nil
+}
+
+// CreateBackup copies sourcePath into backupDir, or ~/.config/costa/backups/<app>
+// when backupDir is empty, as <name>-<timestamp><ext> with a manifest, then
+// applies the configured retention policy. It returns "" when the source does
+// not exist, since there is nothing to back up.
+func CreateBackup(app string, scope Scope, sourcePath, backupDir string) (string, error) {
+	data, err := os.ReadFile(sourcePath)
+	if os.IsNotExist(err) {
+		return "", nil // No backup needed for non-existent file
+	}
+	if err != nil {
+		return "", err
+	}
+
+	// Determine backup directory
+	if backupDir == "" {
+		root, err := BackupsDir()
+		if err != nil {
+			return "", err
+		}
+		backupDir = filepath.Join(root, app)
+	}
+
+	// Create backup directory
+	if err := os.MkdirAll(backupDir, 0700); err != nil {
+		return "", err
+	}
+
+	existing, err := readManifests(backupDir)
+	if err != nil {
+		return "", err
+	}
+	ids := map[string]bool{}
+	for _, m := range existing {
+		ids[m.ID] = true
+	}
+
+	// Backups made in the same second get a numbered suffix instead of
+	// overwriting each other
+	now := time.Now()
+	ext := filepath.Ext(sourcePath)
+	name := strings.TrimSuffix(filepath.Base(sourcePath), ext)
+	stamp := now.Format("20060102-150405")
+	manifest := Manifest{Created: now, App: app, Scope: scope, SourcePath: sourcePath, CLIVersion: version.Get()}
+	for n := 1; ; n++ {
+		suffix := stamp
+		if n > 1 {
+			suffix = fmt.Sprintf("%s-%d", stamp, n)
+		}
+		manifest.ID = app + "-" + suffix
+		manifest.BackupPath = filepath.Join(backupDir, name+"-"+suffix+ext)
+		if _, err := os.Stat(manifest.BackupPath); !ids[manifest.ID] && os.IsNotExist(err) {
+			break
+		}
+	}
+
+	if err := os.WriteFile(manifest.BackupPath, data, 0600); err != nil {
+		return "", err
+	}
+	if err := writeManifest(manifest); err != nil {
+		return "", err
+	}
+
+	if err := applyRetention(append(existing, manifest), now); err != nil {
+		debug.Printf("Failed to prune backups in %s: %v\n", backupDir, err)
+	}
+
+	return manifest.BackupPath, nil
+}
+
+// applyRetention prunes backups according to the [backups] config section
+func applyRetention(manifests []Manifest, now time.Time) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	policy := cfg.Backups
+	if policy.Keep <= 0 && policy.MaxAgeDays <= 0 {
+		return nil
+	}
+	olderThan := time.Duration(policy.MaxAgeDays) * 24 * time.Hour
+	for _, m := range selectPrunable(manifests, policy.Keep, olderThan, now) {
+		if err := deleteBackup(m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ListBackups returns the backups of app, or of every app when app is empty,
+// newest first. Only backups in the default directory are listed.
+func ListBackups(app string) ([]Manifest, error) {
+	root, err := BackupsDir()
+	if err != nil {
+		return nil, err
+	}
+
+	var dirs []string
+	if app != "" {
+		dirs = []string{filepath.Join(root, app)}
+	} else {
+		entries, err := os.ReadDir(root)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		for _, entry := range entries {
+			if entry.IsDir() {
+				dirs = append(dirs, filepath.Join(root, entry.Name()))
+			}
+		}
+	}
+
+	var manifests []Manifest
+	for _, dir := range dirs {
+		found, err := readManifests(dir)
+		if err != nil {
+			return nil, err
+		}
+		manifests = append(manifests, found...)
+	}
+	sort.SliceStable(manifests, func(i, j int) bool {
+		if !manifests[i].Created.Equal(manifests[j].Created) {
+			return manifests[i].Created.After(manifests[j].Created)
+		}
+		return manifests[i].ID > manifests[j].ID
+	})
+	return manifests, nil
+}
+
+// FindBackup returns the backup with the given ID
+func FindBackup(id string) (Manifest, error) {
+	manifests, err := ListBackups("")
+	if err != nil {
+		return Manifest{}, err
+	}
+	for _, m := range manifests {
+		if m.ID == id {
+			return m, nil
+		}
+	}
+	return Manifest{}, fmt.Errorf("%w: %s", ErrBackupNotFound, id)
+}
+
+// RestoreBackup writes a backup's contents back to its source path. The
+// current source is backed up first, so a restore can itself be undone; the
+// path of that backup is returned.
+func RestoreBackup(m Manifest, backupDir string) (string, error) {
+	data, err := os.ReadFile(m.BackupPath)
+	if err != nil {
+		return "", err
+	}
+
+	current, err := CreateBackup(m.App, m.Scope, m.SourcePath, backupDir)
+	if err != nil {
+		return "", fmt.Errorf("failed to back up %s: %w", m.SourcePath, err)
+	}
+
+	if err := safefile.Write(m.SourcePath, data, 0600); err != nil {
+		return current, err
+	}
+	return current, nil
+}
+
+// PruneOpts selects the backups PruneBackups removes
+type PruneOpts struct {
+	// App limits pruning to one app's backups
+	App string
+	// OlderThan removes backups older than this; zero removes any beyond Keep
+	OlderThan time.Duration
+	// Keep is the number of newest backups always kept per config file
+	Keep   int
+	DryRun bool
+}
+
+// PruneBackups removes backups beyond the newest Keep of each config file
+// that are older than OlderThan, and returns the removed backups
+func PruneBackups(opts PruneOpts) ([]Manifest, error) {
+	manifests, err := ListBackups(opts.App)
+	if err != nil {
+		return nil, err
+	}
+	pruned := selectPrunable(manifests, opts.Keep, opts.OlderThan, time.Now())
+	if opts.DryRun {
+		return pruned, nil
+	}
+	for _, m := range pruned {
+		if err := deleteBackup(m); err != nil {
+			return nil, err
+		}
+	}
+	return pruned, nil
+}
+
+// selectPrunable returns the backups beyond the newest keep of each source
+// path that are older than olderThan
+func selectPrunable(manifests []Manifest, keep int, olderThan time.Duration, now time.Time) []Manifest {
+	sorted := append([]Manifest(nil), manifests...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Created.After(sorted[j].Created) })
+
+	var pruned []Manifest
+	seen := map[string]int{}
+	for _, m := range sorted {
+		key := m.App + "\x00" + m.SourcePath
+		seen[key]++
+		if seen[key] <= keep {
+			continue
+		}
+		if olderThan > 0 && now.Sub(m.Created) <= olderThan {
+			continue
+		}
+		pruned = append(pruned, m)
+	}
+	return pruned
+}
+
+// readManifests reads the manifests in a backup directory
+func readManifests(dir string) ([]Manifest, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var manifests []Manifest
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), manifestSuffix) {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		var m Manifest
+		if err := json.Unmarshal(data, &m); err != nil {
+			debug.Printf("Skipping invalid backup manifest %s: %v\n", entry.Name(), err)
+			continue
+		}
+		manifests = append(manifests, m)
+	}
+	return manifests, nil
+}
+
+func writeManifest(m Manifest) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(m.BackupPath+manifestSuffix, data, 0600)
+}
+
+// deleteBackup removes a backup and its manifest
+func deleteBackup(m Manifest) error {
+	if err := os.Remove(m.BackupPath); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err := os.Remove(m.BackupPath + manifestSuffix); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
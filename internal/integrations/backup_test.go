@@ -0,0 +1,133 @@
+package integrations
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeSource(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCreateBackup_UniqueWithManifest(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	source := filepath.Join(home, ".claude", "settings.json")
+	writeSource(t, source, `{"a": 1}`)
+
+	// Backups in the same second must not overwrite each other
+	first, err := CreateBackup("claude-code", ScopeUser, source, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := CreateBackup("claude-code", ScopeUser, source, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first == second {
+		t.Fatalf("expected distinct backup paths, got %s twice", first)
+	}
+
+	backups, err := ListBackups("claude-code")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(backups) != 2 || backups[0].ID == backups[1].ID {
+		t.Fatalf("expected two backups with distinct IDs, got %+v", backups)
+	}
+	m := backups[0]
+	if m.App != "claude-code" || m.Scope != ScopeUser || m.SourcePath != source || m.CLIVersion == "" {
+		t.Errorf("unexpected manifest %+v", m)
+	}
+
+	found, err := FindBackup(m.ID)
+	if err != nil || found.BackupPath != m.BackupPath {
+		t.Errorf("FindBackup(%s) = %+v, %v", m.ID, found, err)
+	}
+}
+
+func TestCreateBackup_MissingSource(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	path, err := CreateBackup("codex", ScopeUser, filepath.Join(t.TempDir(), "missing.toml"), "")
+	if err != nil || path != "" {
+		t.Errorf("expected no backup for a missing file, got %q, %v", path, err)
+	}
+}
+
+func TestCreateBackup_AppliesRetention(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	writeSource(t, filepath.Join(home, ".config", "costa", "config.toml"), "[backups]\nkeep = 2\n")
+
+	source := filepath.Join(home, ".codex", "config.toml")
+	writeSource(t, source, "model = \"x\"\n")
+	for range 4 {
+		if _, err := CreateBackup("codex", ScopeUser, source, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	backups, err := ListBackups("codex")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(backups) != 2 {
+		t.Errorf("expected retention to keep 2 backups, got %d", len(backups))
+	}
+}
+
+func TestSelectPrunable(t *testing.T) {
+	now := time.Now()
+	day := 24 * time.Hour
+	manifests := []Manifest{
+		{ID: "a-1", App: "a", SourcePath: "/x", Created: now.Add(-1 * day)},
+		{ID: "a-2", App: "a", SourcePath: "/x", Created: now.Add(-10 * day)},
+		{ID: "a-3", App: "a", SourcePath: "/x", Created: now.Add(-40 * day)},
+		{ID: "a-4", App: "a", SourcePath: "/y", Created: now.Add(-50 * day)},
+	}
+
+	// The newest backup of each file is kept regardless of age
+	pruned := selectPrunable(manifests, 1, 30*day, now)
+	if len(pruned) != 1 || pruned[0].ID != "a-3" {
+		t.Errorf("expected only a-3 pruned, got %+v", pruned)
+	}
+
+	pruned = selectPrunable(manifests, 1, 0, now)
+	if len(pruned) != 2 {
+		t.Errorf("expected a-2 and a-3 pruned, got %+v", pruned)
+	}
+}
+
+func TestRestoreBackup(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	source := filepath.Join(home, ".codex", "config.toml")
+	writeSource(t, source, "model = \"original\"\n")
+	if _, err := CreateBackup("codex", ScopeUser, source, ""); err != nil {
+		t.Fatal(err)
+	}
+	backups, _ := ListBackups("codex")
+	writeSource(t, source, "model = \"changed\"\n")
+
+	previous, err := RestoreBackup(backups[0], "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(source); string(data) != "model = \"original\"\n" {
+		t.Errorf("expected original contents restored, got %q", data)
+	}
+	if data, _ := os.ReadFile(previous); string(data) != "model = \"changed\"\n" {
+		t.Errorf("expected the pre-restore contents backed up, got %q", data)
+	}
+}
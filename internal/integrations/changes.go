@@ -0,0 +1,91 @@
+package integrations
+
+// ChangeAction is what a change does to a key
+type ChangeAction string
+
+const (
+	ChangeAdd    ChangeAction = "add"
+	ChangeUpdate ChangeAction = "update"
+	ChangeRemove ChangeAction = "remove"
+)
+
+// Change is one key an integration sets or removes. Secrets in Old and New
+// are redacted.
+type Change struct {
+	Old    any          `json:"old,omitempty"`
+	New    any          `json:"new,omitempty"`
+	Path   string       `json:"path"`
+	Action ChangeAction `json:"action"`
+}
+
+// NewChange describes setting path from old, which existed if hadOld, to value
+func NewChange(path string, old any, hadOld bool, value any) Change {
+	if !hadOld {
+		return Change{Path: path, Action: ChangeAdd, New: value}
+	}
+	return Change{Path: path, Action: ChangeUpdate, Old: old, New: value}
+}
+
+// String returns the key as listed in ApplyResult.UpdatedKeys
+func (c Change) String() string {
+	if c.Action == ChangeRemove {
+		return c.Path + " (removed)"
+	}
+	return c.Path
+}
+
+// ChangedKeys returns the UpdatedKeys entries for changes
+func ChangedKeys(changes []Change) []string {
+	keys := make([]string, 0, len(changes))
+	for _, c := range changes {
+		keys = append(keys, c.String())
+	}
+	return keys
+}
+
+// RedactChanges replaces secret string values in changes with their redacted form
+func RedactChanges(changes []Change, secrets ...string) {
+	for i := range changes {
+		changes[i].Old = redactValue(changes[i].Old, secrets)
+		changes[i].New = redactValue(changes[i].New, secrets)
+	}
+}
+
+// redactValue redacts strings, including those nested in maps and slices,
+// copying containers rather than changing them
+func redactValue(v any, secrets []string) any {
+	switch v := v.(type) {
+	case string:
+		return Redact(v, secrets...)
+	case map[string]any:
+		redacted := make(map[string]any, len(v))
+		for k, item := range v {
+			redacted[k] = redactValue(item, secrets)
+		}
+		return redacted
+	case []any:
+		redacted := make([]any, len(v))
+		for i, item := range v {
+			redacted[i] = redactValue(item, secrets)
+		}
+		return redacted
+	default:
+		return v
+	}
+}
+
+// FileDiff is a file's contents before and after a change, with secrets redacted
+type FileDiff struct {
+	Path   string `json:"path"`
+	Before string `json:"before"`
+	After  string `json:"after"`
+}
+
+// NewFileDiff returns the redacted before and after contents of path
+func NewFileDiff(path string, before, after []byte, secrets ...string) FileDiff {
+	return FileDiff{
+		Path:   path,
+		Before: Redact(string(before), secrets...),
+		After:  Redact(string(after), secrets...),
+	}
+}
@@ -0,0 +1,36 @@
+package integrations
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRedactChanges(t *testing.T) {
+	changes := []Change{
+		NewChange("env.TOKEN", "sk-old-secret-1234", true, "sk-new-secret-5678"),
+		NewChange("model", nil, false, "costa/auto"),
+		{Path: "env_key", Action: ChangeRemove, Old: "COSTA_TOKEN"},
+		{Path: "provider", Action: ChangeRemove, Old: map[string]any{"token": "sk-old-secret-1234", "port": 1}},
+	}
+	RedactChanges(changes, "sk-old-secret-1234", "sk-new-secret-5678")
+
+	want := []Change{
+		{Path: "env.TOKEN", Action: ChangeUpdate, Old: "sk-old****1234", New: "sk-new****5678"},
+		{Path: "model", Action: ChangeAdd, New: "costa/auto"},
+		{Path: "env_key", Action: ChangeRemove, Old: "COSTA_TOKEN"},
+		{Path: "provider", Action: ChangeRemove, Old: map[string]any{"token": "sk-old****1234", "port": 1}},
+	}
+	if !reflect.DeepEqual(changes, want) {
+		t.Errorf("got %+v, want %+v", changes, want)
+	}
+	if keys := ChangedKeys(changes); !reflect.DeepEqual(keys, []string{"env.TOKEN", "model", "env_key (removed)", "provider (removed)"}) {
+		t.Errorf("ChangedKeys() = %v", keys)
+	}
+}
+
+func TestNewFileDiff_RedactsSecrets(t *testing.T) {
+	d := NewFileDiff("/x", []byte(`token = "short"`), []byte(`token = "sk-live-abcdef123456"`), "short", "sk-live-abcdef123456", "")
+	if d.Before != `token = "****"` || d.After != `token = "sk-liv****3456"` {
+		t.Errorf("got %+v", d)
+	}
+}
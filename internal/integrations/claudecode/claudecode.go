@@ -1,23 +1,62 @@
 package claudecode
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"strings"
-	"time"
+
+	"github.com/spf13/pflag"
 
 	"github.com/costa-app/costa-cli/internal/auth"
 	"github.com/costa-app/costa-cli/internal/debug"
 	"github.com/costa-app/costa-cli/internal/integrations"
+	"github.com/costa-app/costa-cli/internal/jsonedit"
 )
 
 // ClaudeCode implements the Integration interface for Claude Code
 type ClaudeCode struct{}
 
+// installURL explains how to install the Claude CLI
+const installURL = "https://docs.claude.com/en/docs/claude-code/quickstart"
+
+func init() {
+	integrations.Register(integrations.Registration{
+		Name:       "claude-code",
+		Aliases:    []string{"claude", "claude code"},
+		Title:      "Claude Code",
+		ToolName:   "Claude CLI",
+		InstallURL: installURL,
+		Scopes:     []integrations.Scope{integrations.ScopeUser, integrations.ScopeProject},
+		New:        func() integrations.Integration { return New() },
+		Detect:     detect,
+		Secrets:    secrets,
+		Flags: func(fs *pflag.FlagSet, opts *integrations.ApplyOpts) {
+			fs.BoolVar(&opts.RefreshTokenOnly, "refresh-token-only", false, "Only update the authentication token")
+			fs.BoolVar(&opts.RequireInstalled, "require-installed", false, "Fail if Claude CLI is not installed")
+			fs.BoolVar(&opts.EnableStatusLine, "enable-statusline", false, "Enable Claude Code status line")
+			fs.BoolVar(&opts.SkipStatusLine, "skip-statusline", false, "Skip statusline prompt")
+		},
+		Prompts: []integrations.Prompt{{
+			Question: "📊 Would you like to include the Costa status line in Claude Code?\n" +
+				"   This will show your points usage in the Claude Code status bar.\n" +
+				"   Include status line?",
+			Skip: func(opts *integrations.ApplyOpts) bool {
+				return opts.SkipStatusLine || opts.EnableStatusLine || opts.RefreshTokenOnly
+			},
+			Accept: func(opts *integrations.ApplyOpts) {
+				opts.EnableStatusLine = true
+			},
+		}},
+	})
+}
+
 // New creates a new Claude Code integration
 func New() *ClaudeCode {
 	return &ClaudeCode{}
@@ -35,7 +74,7 @@ func (c *ClaudeCode) Apply(ctx context.Context, opts integrations.ApplyOpts) (in
 	// Detect Claude CLI
 	_, claudeInstalled := detectClaudeCLI()
 	if !claudeInstalled && opts.RequireInstalled {
-		return result, fmt.Errorf("Claude CLI not found. Install it first: https://docs.claude.com/en/docs/claude-code/quickstart")
+		return result, fmt.Errorf("Claude CLI not found. Install it first: %s", installURL)
 	}
 
 	// Resolve settings path
@@ -68,33 +107,65 @@ func (c *ClaudeCode) Apply(ctx context.Context, opts integrations.ApplyOpts) (in
 	// Build desired settings
 	desired := buildDesiredSettings(token, opts.EnableStatusLine)
 
+	// Snapshot the settings before merging, which updates env in place
+	before := integrations.CloneMap(existing)
+	wasCosta, _ := checkCostaConfig(existing)
+
 	// Merge settings
-	merged, updatedKeys, unchangedKeys := mergeSettings(existing, desired, opts.RefreshTokenOnly)
+	merged, changes, unchangedKeys := mergeSettings(existing, desired, opts.RefreshTokenOnly)
+	updatedKeys := integrations.ChangedKeys(changes)
+
+	// Tokens are redacted in the reported changes and diffs
+	secrets := []string{token}
+	if env, ok := before["env"].(map[string]any); ok {
+		if oldToken, ok := env["ANTHROPIC_AUTH_TOKEN"].(string); ok {
+			secrets = append(secrets, oldToken)
+		}
+	}
+
+	var files []pendingFile
+	if len(changes) > 0 {
+		paths := make([]string, len(changes))
+		for i, change := range changes {
+			paths[i] = change.Path
+		}
+		file, err := newPendingFile(settingsPath, merged, paths, secrets)
+		if err != nil {
+			return result, fmt.Errorf("failed to encode settings: %w", err)
+		}
+		files = append(files, file)
+	}
 
-	result.UpdatedKeys = updatedKeys
-	result.UnchangedKeys = unchangedKeys
 	// Include onboarding flag in ~/.claude.json if needed
 	homeDir, _ := os.UserHomeDir()
-	onboardingPath := ""
-	var onboardingData map[string]any
-	needsOnboarding := false
 	if homeDir != "" {
-		onboardingPath = filepath.Join(homeDir, ".claude.json")
-		data, err := loadJSONFile(onboardingPath)
+		onboardingPath := filepath.Join(homeDir, ".claude.json")
+		onboardingData, err := loadJSONFile(onboardingPath)
 		if err != nil {
-			if os.IsNotExist(err) {
-				onboardingData = make(map[string]any)
-			} else {
+			if !os.IsNotExist(err) {
 				return result, fmt.Errorf("failed to load onboarding config: %w", err)
 			}
-		} else {
-			onboardingData = data
+			onboardingData = make(map[string]any)
 		}
-		if v, ok := onboardingData["hasCompletedOnboarding"].(bool); !ok || !v {
-			needsOnboarding = true
-			result.UpdatedKeys = append(result.UpdatedKeys, "~/.claude.json.hasCompletedOnboarding")
+		completed, hadCompleted := onboardingData["hasCompletedOnboarding"]
+		if v, ok := completed.(bool); !ok || !v {
+			changes = append(changes, integrations.NewChange("~/.claude.json.hasCompletedOnboarding", completed, hadCompleted, true))
+			onboardingData["hasCompletedOnboarding"] = true
+			file, err := newPendingFile(onboardingPath, onboardingData, []string{"hasCompletedOnboarding"}, secrets)
+			if err != nil {
+				return result, fmt.Errorf("failed to encode onboarding config: %w", err)
+			}
+			files = append(files, file)
 		}
 	}
+
+	integrations.RedactChanges(changes, secrets...)
+	result.Changes = changes
+	result.UpdatedKeys = integrations.ChangedKeys(changes)
+	result.UnchangedKeys = unchangedKeys
+	for _, file := range files {
+		result.Diffs = append(result.Diffs, file.diff)
+	}
 	result.Changed = len(result.UpdatedKeys) > 0
 
 	// If no changes and not dry run, we're done
@@ -107,29 +178,91 @@ func (c *ClaudeCode) Apply(ctx context.Context, opts integrations.ApplyOpts) (in
 		return result, nil
 	}
 
-	// Create backup
-	backupPath, err := createBackup(settingsPath, opts.BackupDir)
-	if err != nil {
-		return result, fmt.Errorf("failed to create backup: %w", err)
+	// Write settings and the onboarding flag together, or neither
+	tx := integrations.NewTransaction(c.Name(), opts, &result)
+	for _, file := range files {
+		tx.Stage(file.path, file.data, validateJSON)
+	}
+	if err := tx.Commit(); err != nil {
+		return result, err
 	}
-	result.BackupPath = backupPath
 
-	// Ensure directory exists
-	if err := os.MkdirAll(filepath.Dir(settingsPath), 0700); err != nil {
-		return result, fmt.Errorf("failed to create config directory: %w", err)
+	// Remember what the user had before Costa, for 'costa setup remove'.
+	// Only settings that were actually written are recorded.
+	if err := recordOriginals(settingsPath, before, updatedKeys, wasCosta); err != nil {
+		return result, fmt.Errorf("settings were updated, but failed to record the original settings: %w", err)
 	}
 
-	// Write settings
-	if err := writeJSONFile(settingsPath, merged); err != nil {
-		return result, fmt.Errorf("failed to write settings: %w", err)
+	return result, nil
+}
+
+// Remove reverts the settings Costa added, restoring the user's previous values
+func (c *ClaudeCode) Remove(ctx context.Context, opts integrations.ApplyOpts) (integrations.ApplyResult, error) {
+	result := integrations.ApplyResult{}
+
+	settingsPath, err := resolveSettingsPath(opts.Scope)
+	if err != nil {
+		return result, fmt.Errorf("failed to resolve settings path: %w", err)
 	}
-	if needsOnboarding {
-		onboardingData["hasCompletedOnboarding"] = true
-		if err := writeJSONFile(onboardingPath, onboardingData); err != nil {
-			return result, fmt.Errorf("failed to write onboarding config: %w", err)
+	result.ConfigPath = settingsPath
+
+	existing, err := loadJSONFile(settingsPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return result, nil
 		}
+		return result, fmt.Errorf("failed to load existing settings: %w", err)
+	}
+
+	originals, err := integrations.LoadOriginals(settingsPath)
+	if err != nil {
+		return result, fmt.Errorf("failed to load original settings: %w", err)
+	}
+
+	// Without recorded originals, only a config still pointing at Costa is
+	// reverted; the user may have switched the token to another provider
+	env, _ := existing["env"].(map[string]any)
+	if len(originals) == 0 && !isCostaBaseURL(env["ANTHROPIC_BASE_URL"]) {
+		return result, nil
+	}
+
+	// The token being removed is redacted in the diff
+	var secrets []string
+	if token, ok := env["ANTHROPIC_AUTH_TOKEN"].(string); ok {
+		secrets = append(secrets, token)
+	}
+
+	changes := integrations.Revert(existing, managedKeys, originals, ownedByCosta(existing))
+	result.UpdatedKeys = integrations.ChangedKeys(changes)
+	result.Changed = len(changes) > 0
+	if !result.Changed {
+		return result, nil
+	}
+
+	paths := make([]string, len(changes))
+	for i, change := range changes {
+		paths[i] = change.Path
+	}
+	integrations.RedactChanges(changes, secrets...)
+	result.Changes = changes
+
+	file, err := newPendingFile(settingsPath, existing, paths, secrets)
+	if err != nil {
+		return result, fmt.Errorf("failed to encode settings: %w", err)
+	}
+	result.Diffs = append(result.Diffs, file.diff)
+	if opts.DryRun {
+		return result, nil
 	}
 
+	tx := integrations.NewTransaction(c.Name(), opts, &result)
+	tx.Stage(file.path, file.data, validateJSON)
+	if err := tx.Commit(); err != nil {
+		return result, err
+	}
+	if err := integrations.DeleteOriginals(settingsPath); err != nil {
+		return result, fmt.Errorf("failed to clear original settings: %w", err)
+	}
 	return result, nil
 }
 
@@ -140,11 +273,7 @@ func (c *ClaudeCode) Status(ctx context.Context, scope integrations.Scope) (inte
 	}
 
 	// Detect Claude CLI
-	claudePath, claudeInstalled := detectClaudeCLI()
-	result.Installed = claudeInstalled
-	if claudeInstalled {
-		result.Version = getClaudeVersion(claudePath)
-	}
+	result.Version, result.Installed = detect()
 
 	// Resolve settings path
 	settingsPath, err := resolveSettingsPath(scope)
@@ -178,15 +307,86 @@ func (c *ClaudeCode) Status(ctx context.Context, scope integrations.Scope) (inte
 	// Extract redacted token
 	if env, ok := existing["env"].(map[string]any); ok {
 		if token, ok := env["ANTHROPIC_AUTH_TOKEN"].(string); ok && token != "" {
-			result.TokenRedacted = redactToken(token)
+			result.TokenRedacted = integrations.RedactToken(token)
 		}
 	}
 
 	return result, nil
 }
 
+// managedKeys are the settings Costa sets, in the order they are reverted
+var managedKeys = []string{
+	"model",
+	"alwaysThinkingEnabled",
+	"statusLine",
+	"env.ANTHROPIC_BASE_URL",
+	"env.ANTHROPIC_AUTH_TOKEN",
+	"env.ANTHROPIC_DEFAULT_TEXT_MODEL",
+	"env.ANTHROPIC_DEFAULT_MESSAGES_MODEL",
+	"env.ANTHROPIC_DEFAULT_TOOL_USE_MODEL",
+	"env.CLAUDE_CODE_SUBAGENT_MODEL",
+	"env.DISABLE_PROMPT_CACHING",
+}
+
+// recordOriginals saves the pre-Costa values of the keys that changed.
+// Settings already configured for Costa before originals were tracked have
+// nothing meaningful to record, so Remove deletes their keys instead.
+func recordOriginals(settingsPath string, before map[string]any, updatedKeys []string, wasCosta bool) error {
+	originals, err := integrations.LoadOriginals(settingsPath)
+	if err != nil {
+		return err
+	}
+	if len(originals) == 0 && wasCosta {
+		return nil
+	}
+	integrations.RecordChanges(originals, before, managedKeys, updatedKeys)
+	return integrations.SaveOriginals(settingsPath, originals)
+}
+
+// ownedByCosta returns a check for whether a managed key still holds a value
+// Costa set, so Remove leaves settings the user has changed since setup alone.
+// The token and flags have no Costa-specific value, so they count as Costa's
+// only while ANTHROPIC_BASE_URL in settings still points at Costa; a user who
+// has switched provider keeps them.
+func ownedByCosta(settings map[string]any) func(key string, value any) bool {
+	env, _ := settings["env"].(map[string]any)
+	usingCosta := isCostaBaseURL(env["ANTHROPIC_BASE_URL"])
+
+	return func(key string, value any) bool {
+		switch key {
+		case "statusLine":
+			statusLine, _ := value.(map[string]any)
+			command, _ := statusLine["command"].(string)
+			return strings.Contains(command, "costa status")
+		case "model", "env.ANTHROPIC_DEFAULT_TEXT_MODEL", "env.ANTHROPIC_DEFAULT_MESSAGES_MODEL",
+			"env.ANTHROPIC_DEFAULT_TOOL_USE_MODEL", "env.CLAUDE_CODE_SUBAGENT_MODEL":
+			return value == "costa/auto"
+		case "env.ANTHROPIC_BASE_URL":
+			return isCostaBaseURL(value)
+		case "alwaysThinkingEnabled", "env.DISABLE_PROMPT_CACHING":
+			return value == true && usingCosta
+		default:
+			return usingCosta
+		}
+	}
+}
+
+// isCostaBaseURL reports whether an ANTHROPIC_BASE_URL value points at Costa
+func isCostaBaseURL(value any) bool {
+	return value == auth.GetBaseURL()+"/api"
+}
+
 // Helper functions
 
+// detect reports whether the Claude CLI is installed and its version
+func detect() (string, bool) {
+	claudePath, ok := detectClaudeCLI()
+	if !ok {
+		return "", false
+	}
+	return getClaudeVersion(claudePath), true
+}
+
 func detectClaudeCLI() (string, bool) {
 	path, err := exec.LookPath("claude")
 	return path, err == nil
@@ -218,6 +418,7 @@ func resolveSettingsPath(scope integrations.Scope) (string, error) {
 	return filepath.Join(home, ".claude", "settings.json"), nil
 }
 
+// loadJSONFile reads a settings file, which may contain JSONC comments
 func loadJSONFile(path string) (map[string]any, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
@@ -225,26 +426,95 @@ func loadJSONFile(path string) (map[string]any, error) {
 	}
 
 	var result map[string]any
-	if err := json.Unmarshal(data, &result); err != nil {
+	if err := json.Unmarshal(jsonedit.Standardize(data), &result); err != nil {
 		return nil, err
 	}
 
 	return result, nil
 }
 
-func writeJSONFile(path string, data map[string]any) error {
-	jsonData, err := json.MarshalIndent(data, "", "  ")
+// pendingFile is a settings file to write and its diff against the current contents
+type pendingFile struct {
+	path string
+	data []byte
+	diff integrations.FileDiff
+}
+
+// newPendingFile edits the keys at paths in the file at path to match data,
+// leaving the rest of the file as it is. New files are encoded from data as a
+// whole; files the editor cannot handle are an error.
+func newPendingFile(path string, data map[string]any, paths, secrets []string) (pendingFile, error) {
+	current, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return pendingFile{}, err
+	}
+
+	var jsonData []byte
+	if len(bytes.TrimSpace(current)) > 0 {
+		// Rewriting the whole file would drop the user's key order and layout
+		jsonData, err = editSettings(current, data, paths)
+		if err != nil {
+			return pendingFile{}, fmt.Errorf("%s cannot be edited in place: %w\nEdit these settings by hand", path, err)
+		}
+	}
+	if jsonData == nil {
+		if jsonData, err = json.MarshalIndent(data, "", "  "); err != nil {
+			return pendingFile{}, err
+		}
+	}
+	return pendingFile{
+		path: path,
+		data: jsonData,
+		diff: integrations.NewFileDiff(path, current, jsonData, secrets...),
+	}, nil
+}
+
+// editSettings sets or deletes each path in the JSON document current to
+// match settings, and checks that the result decodes to settings
+func editSettings(current []byte, settings map[string]any, paths []string) ([]byte, error) {
+	doc, err := jsonedit.Parse(current)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	for _, path := range paths {
+		if value, ok := integrations.LookupPath(settings, path); ok {
+			err = doc.Set(path, value)
+		} else {
+			err = doc.Delete(path)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
 	}
 
-	// Atomic write: write to temp file then rename
-	tmpPath := path + ".tmp"
-	if err := os.WriteFile(tmpPath, jsonData, 0600); err != nil {
-		return err
+	data := doc.Bytes()
+	var got map[string]any
+	if err := json.Unmarshal(jsonedit.Standardize(data), &got); err != nil {
+		return nil, err
 	}
+	if !integrations.SameSettings(got, settings) {
+		return nil, errors.New("edited settings do not match the expected settings")
+	}
+	return data, nil
+}
+
+// secrets returns the auth token in the contents of a settings file
+func secrets(data []byte) []string {
+	var settings map[string]any
+	if err := json.Unmarshal(jsonedit.Standardize(data), &settings); err != nil {
+		return nil
+	}
+	env, _ := settings["env"].(map[string]any)
+	if token, ok := env["ANTHROPIC_AUTH_TOKEN"].(string); ok {
+		return []string{token}
+	}
+	return nil
+}
 
-	return os.Rename(tmpPath, path)
+// validateJSON checks that data is a JSON object Claude Code can read back
+func validateJSON(data []byte) error {
+	var settings map[string]any
+	return json.Unmarshal(jsonedit.Standardize(data), &settings)
 }
 
 func buildDesiredSettings(token string, enableStatusLine bool) map[string]any {
@@ -291,9 +561,9 @@ func buildDesiredSettings(token string, enableStatusLine bool) map[string]any {
 // mergeSettings merges desired settings into existing settings.
 // Always updates values when they differ from desired (no --update flag needed).
 // TODO: In the future, add option to interactively choose which settings to update.
-func mergeSettings(existing, desired map[string]any, refreshTokenOnly bool) (map[string]any, []string, []string) {
+func mergeSettings(existing, desired map[string]any, refreshTokenOnly bool) (map[string]any, []integrations.Change, []string) {
 	merged := make(map[string]any)
-	var updatedKeys []string
+	var changes []integrations.Change
 	var unchangedKeys []string
 
 	// Copy existing
@@ -307,9 +577,9 @@ func mergeSettings(existing, desired map[string]any, refreshTokenOnly bool) (map
 		if env, ok := merged["env"].(map[string]any); ok {
 			if desiredEnv, ok := desired["env"].(map[string]any); ok {
 				if token, ok := desiredEnv["ANTHROPIC_AUTH_TOKEN"].(string); ok {
-					if env["ANTHROPIC_AUTH_TOKEN"] != token {
+					if existingToken, exists := env["ANTHROPIC_AUTH_TOKEN"]; existingToken != token {
 						env["ANTHROPIC_AUTH_TOKEN"] = token
-						updatedKeys = append(updatedKeys, "env.ANTHROPIC_AUTH_TOKEN")
+						changes = append(changes, integrations.NewChange("env.ANTHROPIC_AUTH_TOKEN", existingToken, exists, token))
 					} else {
 						unchangedKeys = append(unchangedKeys, "env.ANTHROPIC_AUTH_TOKEN")
 					}
@@ -321,7 +591,7 @@ func mergeSettings(existing, desired map[string]any, refreshTokenOnly bool) (map
 				merged["env"] = map[string]any{
 					"ANTHROPIC_AUTH_TOKEN": desiredEnv["ANTHROPIC_AUTH_TOKEN"],
 				}
-				updatedKeys = append(updatedKeys, "env.ANTHROPIC_AUTH_TOKEN")
+				changes = append(changes, integrations.NewChange("env.ANTHROPIC_AUTH_TOKEN", nil, false, desiredEnv["ANTHROPIC_AUTH_TOKEN"]))
 			}
 		}
 	} else {
@@ -342,12 +612,9 @@ func mergeSettings(existing, desired map[string]any, refreshTokenOnly bool) (map
 				for envKey, envValue := range desiredEnv {
 					existingVal, exists := existingEnv[envKey]
 
-					if !exists {
+					if !exists || existingVal != envValue {
 						existingEnv[envKey] = envValue
-						updatedKeys = append(updatedKeys, fmt.Sprintf("env.%s", envKey))
-					} else if existingVal != envValue {
-						existingEnv[envKey] = envValue
-						updatedKeys = append(updatedKeys, fmt.Sprintf("env.%s", envKey))
+						changes = append(changes, integrations.NewChange("env."+envKey, existingVal, exists, envValue))
 					} else {
 						unchangedKeys = append(unchangedKeys, fmt.Sprintf("env.%s", envKey))
 					}
@@ -363,9 +630,10 @@ func mergeSettings(existing, desired map[string]any, refreshTokenOnly bool) (map
 				// Check if statusLine changed
 				statusLineChanged := !hasStatusLine
 				if hasStatusLine {
-					// Compare each field
+					// Compare each field; numbers read back from JSON are float64,
+					// so compare their printed form
 					for slKey, slValue := range desiredStatusLine {
-						if existingStatusLine[slKey] != slValue {
+						if fmt.Sprint(existingStatusLine[slKey]) != fmt.Sprint(slValue) {
 							statusLineChanged = true
 							break
 						}
@@ -373,20 +641,17 @@ func mergeSettings(existing, desired map[string]any, refreshTokenOnly bool) (map
 				}
 
 				if statusLineChanged {
+					changes = append(changes, integrations.NewChange("statusLine", existingStatusLine, hasStatusLine, desiredValue))
 					merged["statusLine"] = desiredValue
-					updatedKeys = append(updatedKeys, "statusLine")
 				} else {
 					unchangedKeys = append(unchangedKeys, "statusLine")
 				}
 			} else {
 				// Top-level keys - always update when different
 				existingVal, exists := merged[key]
-				if !exists {
-					merged[key] = desiredValue
-					updatedKeys = append(updatedKeys, key)
-				} else if existingVal != desiredValue {
+				if !exists || existingVal != desiredValue {
 					merged[key] = desiredValue
-					updatedKeys = append(updatedKeys, key)
+					changes = append(changes, integrations.NewChange(key, existingVal, exists, desiredValue))
 				} else {
 					unchangedKeys = append(unchangedKeys, key)
 				}
@@ -394,7 +659,11 @@ func mergeSettings(existing, desired map[string]any, refreshTokenOnly bool) (map
 		}
 	}
 
-	return merged, updatedKeys, unchangedKeys
+	// Report keys in a stable order; desired settings are maps
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	sort.Strings(unchangedKeys)
+
+	return merged, changes, unchangedKeys
 }
 
 func checkCostaConfig(settings map[string]any) (bool, []string) {
@@ -426,47 +695,3 @@ func checkCostaConfig(settings map[string]any) (bool, []string) {
 
 	return len(missing) == 0, missing
 }
-
-func createBackup(sourcePath, backupDir string) (string, error) {
-	// Check if source exists
-	if _, err := os.Stat(sourcePath); os.IsNotExist(err) {
-		return "", nil // No backup needed for non-existent file
-	}
-
-	// Determine backup directory
-	if backupDir == "" {
-		configDir, err := auth.GetConfigDir()
-		if err != nil {
-			return "", err
-		}
-		backupDir = filepath.Join(configDir, "backups", "claude-code")
-	}
-
-	// Create backup directory
-	if err := os.MkdirAll(backupDir, 0700); err != nil {
-		return "", err
-	}
-
-	// Generate backup filename with timestamp
-	timestamp := time.Now().Format("20060102-150405")
-	backupPath := filepath.Join(backupDir, fmt.Sprintf("settings-%s.json", timestamp))
-
-	// Copy file
-	data, err := os.ReadFile(sourcePath)
-	if err != nil {
-		return "", err
-	}
-
-	if err := os.WriteFile(backupPath, data, 0600); err != nil {
-		return "", err
-	}
-
-	return backupPath, nil
-}
-
-func redactToken(token string) string {
-	if len(token) <= 10 {
-		return "****"
-	}
-	return token[:6] + "****" + token[len(token)-4:]
-}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/costa-app/costa-cli/internal/integrations"
@@ -425,3 +426,183 @@ func TestClaudeCodeSetup_DryRunDoesNotWrite(t *testing.T) {
 		t.Errorf("Token was changed during dry run: got %v", token)
 	}
 }
+
+func TestClaudeCodeSetup_StatusLineIsIdempotent(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	opts := integrations.ApplyOpts{
+		Scope:            integrations.ScopeUser,
+		TokenOverride:    "test-token",
+		EnableStatusLine: true,
+	}
+	if _, err := New().Apply(context.Background(), opts); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	// The status line padding is read back from JSON as a float64
+	opts.DryRun = true
+	result, err := New().Apply(context.Background(), opts)
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if result.Changed {
+		t.Errorf("expected no changes on second apply, got %v", result.UpdatedKeys)
+	}
+}
+
+func TestClaudeCodeSetup_BacksUpEveryFile(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	settingsPath := filepath.Join(home, ".claude", "settings.json")
+	onboardingPath := filepath.Join(home, ".claude.json")
+	if err := os.MkdirAll(filepath.Dir(settingsPath), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(settingsPath, []byte(`{"theme": "dark"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(onboardingPath, []byte(`{"numStartups": 3}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	backupDir := filepath.Join(home, "backups")
+	result, err := New().Apply(context.Background(), integrations.ApplyOpts{
+		Scope:         integrations.ScopeUser,
+		TokenOverride: "test-token",
+		BackupDir:     backupDir,
+	})
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	if len(result.BackupPaths) != 2 || result.BackupPath != result.BackupPaths[0] {
+		t.Fatalf("expected backups of settings.json and .claude.json, got %v", result.BackupPaths)
+	}
+	for i, want := range []string{`{"theme": "dark"}`, `{"numStartups": 3}`} {
+		if filepath.Dir(result.BackupPaths[i]) != backupDir {
+			t.Errorf("expected backup in %s, got %s", backupDir, result.BackupPaths[i])
+		}
+		if data, _ := os.ReadFile(result.BackupPaths[i]); string(data) != want {
+			t.Errorf("backup %d = %s, want %s", i, data, want)
+		}
+	}
+}
+
+func TestClaudeCodeSetup_FailedCommitRecordsNoOriginals(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	settingsPath := filepath.Join(home, ".claude", "settings.json")
+	if err := os.MkdirAll(filepath.Dir(settingsPath), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(settingsPath, []byte(`{"model": "opus"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	// A backup directory that cannot be created makes the commit fail
+	backupDir := filepath.Join(home, "not-a-dir")
+	if err := os.WriteFile(backupDir, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := New().Apply(context.Background(), integrations.ApplyOpts{Scope: integrations.ScopeUser, TokenOverride: "test-token", BackupDir: backupDir})
+	if err == nil {
+		t.Fatal("expected Apply to fail when the backup fails")
+	}
+
+	originals, err := integrations.LoadOriginals(settingsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(originals) > 0 {
+		t.Errorf("expected no originals for settings that were never written, got %v", originals)
+	}
+}
+
+func TestClaudeCodeSetup_PreservesOrderAndComments(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	settingsPath := filepath.Join(home, ".claude", "settings.json")
+	if err := os.MkdirAll(filepath.Dir(settingsPath), 0700); err != nil {
+		t.Fatal(err)
+	}
+	original := `{
+    // Synced from my dotfiles
+    "theme": "dark",
+    "model": "opus",
+    "cleanupPeriodDays": 30.0,
+    "permissions": {
+        "allow": ["Bash(git diff:*)"]
+    },
+}
+`
+	if err := os.WriteFile(settingsPath, []byte(original), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	opts := integrations.ApplyOpts{Scope: integrations.ScopeUser, TokenOverride: "test-token"}
+	result, err := New().Apply(ctx, opts)
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if len(result.Warnings) > 0 {
+		t.Errorf("expected an in-place edit, got warnings %v", result.Warnings)
+	}
+
+	data, err := os.ReadFile(settingsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := string(data)
+	for _, want := range []string{
+		"    // Synced from my dotfiles\n    \"theme\": \"dark\",\n    \"model\": \"costa/auto\",\n    \"cleanupPeriodDays\": 30.0,\n",
+		"    \"env\": {\n        \"ANTHROPIC_AUTH_TOKEN\": \"test-token\",\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected settings to contain %q, got:\n%s", want, got)
+		}
+	}
+	if !strings.HasSuffix(got, "\n}\n") {
+		t.Errorf("expected the trailing newline to be kept, got:\n%s", got)
+	}
+
+	if _, err := New().Remove(ctx, opts); err != nil {
+		t.Fatalf("Remove failed: %v", err)
+	}
+	if data, _ := os.ReadFile(settingsPath); string(data) != original {
+		t.Errorf("settings after remove:\n%s\nwant the original:\n%s", data, original)
+	}
+}
+
+func TestParseStatusLineInput(t *testing.T) {
+	input := ParseStatusLineInput([]byte(`{
+		"session_id": "abc123",
+		"transcript_path": "/tmp/transcript.json",
+		"cwd": "/home/dev/project/src",
+		"model": {"id": "claude-opus-4-1", "display_name": "Opus"},
+		"workspace": {"current_dir": "/home/dev/project/src", "project_dir": "/home/dev/project"},
+		"cost": {"total_cost_usd": 0.42, "total_lines_added": 10}
+	}`))
+	if input == nil {
+		t.Fatal("expected status line input to parse")
+	}
+	if input.ModelName() != "Opus" {
+		t.Errorf("expected model Opus, got %q", input.ModelName())
+	}
+	if input.ProjectName() != "project" {
+		t.Errorf("expected project name 'project', got %q", input.ProjectName())
+	}
+	if input.Cost.TotalCostUSD != 0.42 {
+		t.Errorf("expected cost 0.42, got %v", input.Cost.TotalCostUSD)
+	}
+
+	if ParseStatusLineInput([]byte("not json")) != nil {
+		t.Error("expected nil for invalid input")
+	}
+	if ParseStatusLineInput([]byte(`{"cwd": "/tmp"}`)) != nil {
+		t.Error("expected nil for input without a session id")
+	}
+}
@@ -0,0 +1,70 @@
+package claudecode
+
+import (
+	"encoding/json"
+	"path/filepath"
+)
+
+// StatusLineInput is the JSON document Claude Code pipes to its status line command
+type StatusLineInput struct {
+	SessionID      string `json:"session_id"`
+	TranscriptPath string `json:"transcript_path"`
+	Cwd            string `json:"cwd"`
+	Version        string `json:"version"`
+	Model          struct {
+		ID          string `json:"id"`
+		DisplayName string `json:"display_name"`
+	} `json:"model"`
+	Workspace struct {
+		CurrentDir string `json:"current_dir"`
+		ProjectDir string `json:"project_dir"`
+	} `json:"workspace"`
+	Cost StatusLineCost `json:"cost"`
+}
+
+// StatusLineCost is the session cost summary reported by Claude Code
+type StatusLineCost struct {
+	TotalCostUSD       float64 `json:"total_cost_usd"`
+	TotalDurationMS    int64   `json:"total_duration_ms"`
+	TotalAPIDurationMS int64   `json:"total_api_duration_ms"`
+	TotalLinesAdded    int     `json:"total_lines_added"`
+	TotalLinesRemoved  int     `json:"total_lines_removed"`
+}
+
+// ParseStatusLineInput decodes status line input.
+// It returns nil for anything that is not a Claude Code session document.
+func ParseStatusLineInput(data []byte) *StatusLineInput {
+	var input StatusLineInput
+	if err := json.Unmarshal(data, &input); err != nil || input.SessionID == "" {
+		return nil
+	}
+	return &input
+}
+
+// ProjectDir returns the workspace project directory, falling back to the cwd
+func (in *StatusLineInput) ProjectDir() string {
+	if in.Workspace.ProjectDir != "" {
+		return in.Workspace.ProjectDir
+	}
+	if in.Workspace.CurrentDir != "" {
+		return in.Workspace.CurrentDir
+	}
+	return in.Cwd
+}
+
+// ProjectName returns the base name of the project directory
+func (in *StatusLineInput) ProjectName() string {
+	dir := in.ProjectDir()
+	if dir == "" {
+		return ""
+	}
+	return filepath.Base(dir)
+}
+
+// ModelName returns the model's display name, falling back to its ID
+func (in *StatusLineInput) ModelName() string {
+	if in.Model.DisplayName != "" {
+		return in.Model.DisplayName
+	}
+	return in.Model.ID
+}
@@ -2,15 +2,19 @@ package codex
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"strings"
 
 	"github.com/pelletier/go-toml/v2"
 
 	"github.com/costa-app/costa-cli/internal/auth"
 	"github.com/costa-app/costa-cli/internal/debug"
 	"github.com/costa-app/costa-cli/internal/integrations"
+	"github.com/costa-app/costa-cli/internal/tomledit"
 )
 
 // Codex implements the Integration interface for Codex CLI
@@ -18,12 +22,26 @@ import (
 
 type Codex struct{}
 
+func init() {
+	integrations.Register(integrations.Registration{
+		Name:       "codex",
+		Title:      "Codex",
+		ToolName:   "Codex CLI",
+		InstallURL: "https://github.com/openai/codex",
+		Scopes:     []integrations.Scope{integrations.ScopeUser},
+		New:        func() integrations.Integration { return New() },
+		Detect:     detect,
+		Secrets:    secrets,
+	})
+}
+
 func New() *Codex { return &Codex{} }
 
 func (c *Codex) Name() string { return "codex" }
 
 // Apply configures Codex per user scope only (project scope not supported)
 func (c *Codex) Apply(ctx context.Context, opts integrations.ApplyOpts) (integrations.ApplyResult, error) {
+	opts.Scope = integrations.ScopeUser // Codex only has a user config
 	res := integrations.ApplyResult{}
 
 	// Resolve config path
@@ -70,39 +88,254 @@ func (c *Codex) Apply(ctx context.Context, opts integrations.ApplyOpts) (integra
 		},
 	}
 
+	// Snapshot before merging, which updates nested tables in place
+	before := integrations.CloneMap(existing)
+	wasCosta := existing["model_provider"] == "costa"
+
 	// Merge desired into existing
-	updated, updatedKeys := mergeToml(existing, desired)
+	updated, changes := mergeToml(existing, desired)
+	updatedKeys := integrations.ChangedKeys(changes)
 	res.UpdatedKeys = updatedKeys
 	res.Changed = len(updatedKeys) > 0
+	if !res.Changed {
+		return res, nil
+	}
+
+	// Tokens are redacted in the reported changes and diffs
+	secrets := []string{codingToken, bearerToken(before)}
+	integrations.RedactChanges(changes, secrets...)
+	res.Changes = changes
 
-	if opts.DryRun || !res.Changed {
+	paths := make([]string, len(changes))
+	for i, change := range changes {
+		paths[i] = change.Path
+	}
+	file, err := newPendingFile(cfgPath, updated, paths, secrets)
+	if err != nil {
+		return res, err
+	}
+	res.Diffs = append(res.Diffs, file.diff)
+
+	if opts.DryRun {
 		return res, nil
 	}
 
-	// Ensure directory exists
-	if err := os.MkdirAll(filepath.Dir(cfgPath), 0700); err != nil {
+	if err := file.commit(integrations.NewTransaction(c.Name(), opts, &res)); err != nil {
 		return res, err
 	}
 
-	// Write TOML atomically
-	bytes, err := toml.Marshal(updated)
+	// Remember what the user had before Costa, for 'costa setup remove'.
+	// Only settings that were actually written are recorded.
+	if err := recordOriginals(cfgPath, before, updatedKeys, wasCosta); err != nil {
+		return res, fmt.Errorf("config was updated, but failed to record the original settings: %w", err)
+	}
+	return res, nil
+}
+
+// recordOriginals saves the pre-Costa values of the keys that changed.
+// Configs set up before originals were tracked have nothing to record.
+func recordOriginals(cfgPath string, before map[string]any, updatedKeys []string, wasCosta bool) error {
+	originals, err := integrations.LoadOriginals(cfgPath)
+	if err != nil {
+		return err
+	}
+	if len(originals) == 0 && wasCosta {
+		return nil
+	}
+	integrations.RecordChanges(originals, before, managedKeys, updatedKeys)
+	return integrations.SaveOriginals(cfgPath, originals)
+}
+
+// Remove strips the Costa provider and restores the model settings the user had before
+func (c *Codex) Remove(ctx context.Context, opts integrations.ApplyOpts) (integrations.ApplyResult, error) {
+	opts.Scope = integrations.ScopeUser // Codex only has a user config
+	res := integrations.ApplyResult{}
+
+	cfgPath, err := resolveConfigPath()
 	if err != nil {
 		return res, err
 	}
-	tmp := cfgPath + ".tmp"
-	if err := os.WriteFile(tmp, bytes, 0600); err != nil {
+	res.ConfigPath = cfgPath
+
+	data, err := os.ReadFile(cfgPath)
+	if os.IsNotExist(err) {
+		return res, nil
+	}
+	if err != nil {
 		return res, err
 	}
-	if err := os.Rename(tmp, cfgPath); err != nil {
+	existing := map[string]any{}
+	if err := toml.Unmarshal(data, &existing); err != nil {
+		return res, fmt.Errorf("failed parsing %s: %w", cfgPath, err)
+	}
+
+	originals, err := integrations.LoadOriginals(cfgPath)
+	if err != nil {
+		return res, fmt.Errorf("failed to load original settings: %w", err)
+	}
+
+	// Without recorded originals, only a config still using the Costa provider is reverted
+	if len(originals) == 0 && existing["model_provider"] != "costa" {
+		return res, nil
+	}
+
+	// The token being removed is redacted in the diff
+	secrets := []string{bearerToken(existing)}
+
+	changes := integrations.Revert(existing, managedKeys, originals, ownedByCosta(existing))
+	res.UpdatedKeys = integrations.ChangedKeys(changes)
+	res.Changed = len(changes) > 0
+	if !res.Changed {
+		return res, nil
+	}
+
+	paths := make([]string, len(changes))
+	for i, change := range changes {
+		paths[i] = change.Path
+	}
+	integrations.RedactChanges(changes, secrets...)
+	res.Changes = changes
+
+	file, err := newPendingFile(cfgPath, existing, paths, secrets)
+	if err != nil {
 		return res, err
 	}
+	res.Diffs = append(res.Diffs, file.diff)
+	if opts.DryRun {
+		return res, nil
+	}
 
+	if err := file.commit(integrations.NewTransaction(c.Name(), opts, &res)); err != nil {
+		return res, err
+	}
+	if err := integrations.DeleteOriginals(cfgPath); err != nil {
+		return res, fmt.Errorf("failed to clear original settings: %w", err)
+	}
 	return res, nil
 }
 
+// managedKeys are the settings Costa sets, in the order they are reverted
+var managedKeys = []string{
+	"model_provider",
+	"model",
+	"features.web_search_request",
+	"model_providers.costa",
+}
+
+// ownedByCosta returns a check for whether a managed key still holds a value
+// Costa set. The Costa provider table counts as Costa's only while it still
+// points at Costa or is still the selected provider.
+func ownedByCosta(config map[string]any) func(key string, value any) bool {
+	usingCosta := config["model_provider"] == "costa"
+
+	return func(key string, value any) bool {
+		switch key {
+		case "model_provider":
+			return value == "costa"
+		case "model":
+			return value == "costa/auto"
+		case "features.web_search_request":
+			return value == true && usingCosta
+		case "model_providers.costa":
+			provider, _ := value.(map[string]any)
+			return usingCosta || provider["base_url"] == auth.GetBaseURL()+"/api/v1"
+		default:
+			return usingCosta
+		}
+	}
+}
+
+// pendingFile is the TOML config to write and its diff against the current contents
+type pendingFile struct {
+	path string
+	data []byte
+	diff integrations.FileDiff
+}
+
+// newPendingFile edits the keys at paths in cfgPath to match config, leaving
+// the rest of the file as it is. Layouts the editor cannot handle are an error.
+func newPendingFile(cfgPath string, config map[string]any, paths, secrets []string) (pendingFile, error) {
+	current, err := os.ReadFile(cfgPath)
+	if err != nil && !os.IsNotExist(err) {
+		return pendingFile{}, err
+	}
+	// Rewriting the whole file would drop the user's comments and layout
+	data, err := editConfig(current, config, paths)
+	if err != nil {
+		return pendingFile{}, fmt.Errorf("%s cannot be edited in place: %w\nMove these settings out of inline tables and arrays of tables, or edit them by hand", cfgPath, err)
+	}
+	return pendingFile{
+		path: cfgPath,
+		data: data,
+		diff: integrations.NewFileDiff(cfgPath, current, data, secrets...),
+	}, nil
+}
+
+// editConfig sets or deletes each path in the TOML document current to match
+// config, and checks that the result decodes to config
+func editConfig(current []byte, config map[string]any, paths []string) ([]byte, error) {
+	doc, err := tomledit.Parse(current)
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range paths {
+		if value, ok := integrations.LookupPath(config, path); ok {
+			err = doc.Set(path, value)
+		} else {
+			err = doc.Delete(path)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+
+	data := doc.Bytes()
+	var got map[string]any
+	if err := toml.Unmarshal(data, &got); err != nil {
+		return nil, err
+	}
+	if !integrations.SameSettings(got, config) {
+		return nil, errors.New("edited config does not match the expected settings")
+	}
+	return data, nil
+}
+
+// commit backs up and rewrites the config
+func (f pendingFile) commit(tx *integrations.Transaction) error {
+	tx.Stage(f.path, f.data, validateTOML)
+	return tx.Commit()
+}
+
+// bearerToken returns the Costa provider's token in config, if any
+func bearerToken(config map[string]any) string {
+	providers, _ := config["model_providers"].(map[string]any)
+	costa, _ := providers["costa"].(map[string]any)
+	token, _ := costa["experimental_bearer_token"].(string)
+	return token
+}
+
+// validateTOML checks that data parses as a TOML document
+func validateTOML(data []byte) error {
+	var config map[string]any
+	return toml.Unmarshal(data, &config)
+}
+
+// secrets returns the Costa provider's token in the contents of a config file
+func secrets(data []byte) []string {
+	var config map[string]any
+	if err := toml.Unmarshal(data, &config); err != nil {
+		return nil
+	}
+	if token := bearerToken(config); token != "" {
+		return []string{token}
+	}
+	return nil
+}
+
 // Status reports Codex status
 func (c *Codex) Status(ctx context.Context, scope integrations.Scope) (integrations.StatusResult, error) {
 	res := integrations.StatusResult{Scope: integrations.ScopeUser}
+	res.Version, res.Installed = detect()
 
 	cfgPath, err := resolveConfigPath()
 	if err != nil {
@@ -126,6 +359,19 @@ func (c *Codex) Status(ctx context.Context, scope integrations.Scope) (integrati
 	return res, nil
 }
 
+// detect reports whether the codex CLI is on PATH and its version
+func detect() (string, bool) {
+	codexPath, err := exec.LookPath("codex")
+	if err != nil {
+		return "", false
+	}
+	output, err := exec.Command(codexPath, "--version").Output() // #nosec G204 -- path comes from LookPath
+	if err != nil {
+		return "unknown", true
+	}
+	return strings.TrimSpace(string(output)), true
+}
+
 func resolveConfigPath() (string, error) {
 	h, err := os.UserHomeDir()
 	if err != nil {
@@ -135,25 +381,25 @@ func resolveConfigPath() (string, error) {
 }
 
 // mergeToml does a shallow merge and tracks updated keys
-func mergeToml(existing, desired map[string]any) (map[string]any, []string) {
+func mergeToml(existing, desired map[string]any) (map[string]any, []integrations.Change) {
 	updated := map[string]any{}
 	for k, v := range existing {
 		updated[k] = v
 	}
 
-	var updatedKeys []string
-	apply := func(path string) {
-		updatedKeys = append(updatedKeys, path)
+	var changes []integrations.Change
+	apply := func(path string, table map[string]any, key string, value any) {
+		old, exists := table[key]
+		changes = append(changes, integrations.NewChange(path, old, exists, value))
+		table[key] = value
 	}
 
 	// top-level
 	if existing["model_provider"] != desired["model_provider"] {
-		updated["model_provider"] = desired["model_provider"]
-		apply("model_provider")
+		apply("model_provider", updated, "model_provider", desired["model_provider"])
 	}
 	if existing["model"] != desired["model"] {
-		updated["model"] = desired["model"]
-		apply("model")
+		apply("model", updated, "model", desired["model"])
 	}
 
 	// features
@@ -162,9 +408,8 @@ func mergeToml(existing, desired map[string]any) (map[string]any, []string) {
 		feat = v
 	}
 	if feat["web_search_request"] != true {
-		feat["web_search_request"] = true
+		apply("features.web_search_request", feat, "web_search_request", true)
 		updated["features"] = feat
-		apply("features.web_search_request")
 	}
 
 	// providers.costa
@@ -178,42 +423,39 @@ func mergeToml(existing, desired map[string]any) (map[string]any, []string) {
 	}
 
 	if costa["name"] != "costa" {
-		costa["name"] = "costa"
-		apply("model_providers.costa.name")
+		apply("model_providers.costa.name", costa, "name", "costa")
 	}
 	base := auth.GetBaseURL() + "/api/v1"
 	if costa["base_url"] != base {
-		costa["base_url"] = base
-		apply("model_providers.costa.base_url")
+		apply("model_providers.costa.base_url", costa, "base_url", base)
 	}
 
 	// Get the desired token from desired map
 	desiredProviders, ok := desired["model_providers"].(map[string]any)
 	if !ok {
-		return updated, updatedKeys
+		return updated, changes
 	}
 	desiredCosta, ok := desiredProviders["costa"].(map[string]any)
 	if !ok {
-		return updated, updatedKeys
+		return updated, changes
 	}
 	desiredToken, ok := desiredCosta["experimental_bearer_token"].(string)
 	if !ok {
-		return updated, updatedKeys
+		return updated, changes
 	}
 
 	if costa["experimental_bearer_token"] != desiredToken {
-		costa["experimental_bearer_token"] = desiredToken
-		apply("model_providers.costa.experimental_bearer_token")
+		apply("model_providers.costa.experimental_bearer_token", costa, "experimental_bearer_token", desiredToken)
 	}
 
 	// Remove old env_key if present
-	if _, hasEnvKey := costa["env_key"]; hasEnvKey {
+	if envKey, hasEnvKey := costa["env_key"]; hasEnvKey {
 		delete(costa, "env_key")
-		apply("model_providers.costa.env_key (removed)")
+		changes = append(changes, integrations.Change{Path: "model_providers.costa.env_key", Action: integrations.ChangeRemove, Old: envKey})
 	}
 
 	providers["costa"] = costa
 	updated["model_providers"] = providers
 
-	return updated, updatedKeys
+	return updated, changes
 }
@@ -25,8 +25,17 @@ type ApplyOpts struct {
 
 // ApplyResult contains the result of applying configuration
 type ApplyResult struct {
-	BackupPath    string
-	ConfigPath    string
+	// BackupPath is the backup of the first file written, usually ConfigPath
+	BackupPath string
+	ConfigPath string
+	// BackupPaths lists the backups of every file written
+	BackupPaths []string
+	// Committed lists the files written, once all of them were
+	Committed []string
+	// Changes describes each updated key; UpdatedKeys lists their paths
+	Changes []Change
+	// Diffs holds the contents of each changed file before and after
+	Diffs         []FileDiff
 	UpdatedKeys   []string
 	UnchangedKeys []string
 	Warnings      []string
@@ -54,6 +63,10 @@ type Integration interface {
 	// Apply applies the integration configuration
 	Apply(ctx context.Context, opts ApplyOpts) (ApplyResult, error)
 
+	// Remove reverts the configuration Apply added, restoring values the
+	// user had before. Changes describes each reverted key.
+	Remove(ctx context.Context, opts ApplyOpts) (ApplyResult, error)
+
 	// Status returns the current status of the integration
 	Status(ctx context.Context, scope Scope) (StatusResult, error)
 }
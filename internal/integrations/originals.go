@@ -0,0 +1,253 @@
+package integrations
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/costa-app/costa-cli/internal/config"
+	"github.com/costa-app/costa-cli/internal/safefile"
+)
+
+// Original is the value a key had before Costa first changed it
+type Original struct {
+	Value   any  `json:"value,omitempty"`
+	Existed bool `json:"existed"`
+}
+
+// Originals maps dotted key paths in one config file to their original values
+type Originals map[string]Original
+
+// Record remembers a key's value before Costa changes it. Only the first
+// change is recorded, so later runs never overwrite the user's original.
+func (o Originals) Record(key string, value any, existed bool) {
+	if _, ok := o[key]; ok {
+		return
+	}
+	o[key] = Original{Value: value, Existed: existed}
+}
+
+// originalsPath returns the file holding the originals of every configured file
+func originalsPath() (string, error) {
+	dir, err := config.Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "setup-originals.json"), nil
+}
+
+func loadAllOriginals() (map[string]Originals, error) {
+	path, err := originalsPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return map[string]Originals{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	all := map[string]Originals{}
+	if err := json.Unmarshal(data, &all); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return all, nil
+}
+
+func saveAllOriginals(all map[string]Originals) error {
+	path, err := originalsPath()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(all, "", "  ")
+	if err != nil {
+		return err
+	}
+	return safefile.Write(path, data, 0600)
+}
+
+// LoadOriginals returns the recorded originals for a config file
+func LoadOriginals(configPath string) (Originals, error) {
+	all, err := loadAllOriginals()
+	if err != nil {
+		return nil, err
+	}
+	if o, ok := all[configPath]; ok {
+		return o, nil
+	}
+	return Originals{}, nil
+}
+
+// SaveOriginals stores the originals for a config file
+func SaveOriginals(configPath string, o Originals) error {
+	all, err := loadAllOriginals()
+	if err != nil {
+		return err
+	}
+	all[configPath] = o
+	return saveAllOriginals(all)
+}
+
+// DeleteOriginals forgets a config file's originals once it has been reverted
+func DeleteOriginals(configPath string) error {
+	all, err := loadAllOriginals()
+	if err != nil {
+		return err
+	}
+	if _, ok := all[configPath]; !ok {
+		return nil
+	}
+	delete(all, configPath)
+	return saveAllOriginals(all)
+}
+
+// RecordChanges records the values that managed keys had in before (the
+// settings as loaded, prior to merging) for every managed key touched by
+// updatedKeys, e.g. "model_providers.costa.base_url" touches
+// "model_providers.costa"
+func RecordChanges(o Originals, before map[string]any, managed, updatedKeys []string) {
+	for _, key := range managed {
+		for _, updated := range updatedKeys {
+			if updated == key || strings.HasPrefix(updated, key+".") {
+				value, ok := LookupPath(before, key)
+				o.Record(key, value, ok)
+				break
+			}
+		}
+	}
+}
+
+// CloneMap deep-copies nested maps and slices so a snapshot survives in-place merges
+func CloneMap(m map[string]any) map[string]any {
+	if m == nil {
+		return nil
+	}
+	clone := make(map[string]any, len(m))
+	for k, v := range m {
+		clone[k] = cloneValue(v)
+	}
+	return clone
+}
+
+func cloneValue(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		return CloneMap(v)
+	case []any:
+		clone := make([]any, len(v))
+		for i, item := range v {
+			clone[i] = cloneValue(item)
+		}
+		return clone
+	default:
+		return v
+	}
+}
+
+// Revert strips the managed keys Costa set from settings, restoring any value
+// recorded in originals. owned reports whether a key's current value still
+// belongs to Costa; keys the user has since changed are left alone.
+// It returns a change for each reverted key; values are not redacted.
+func Revert(settings map[string]any, managed []string, originals Originals, owned func(key string, value any) bool) []Change {
+	var changes []Change
+	for _, key := range managed {
+		current, ok := LookupPath(settings, key)
+		if !ok || (owned != nil && !owned(key, current)) {
+			continue
+		}
+		if orig, ok := originals[key]; ok && orig.Existed {
+			if reflect.DeepEqual(orig.Value, current) {
+				continue
+			}
+			SetPath(settings, key, orig.Value)
+			changes = append(changes, Change{Path: key, Action: ChangeUpdate, Old: current, New: orig.Value})
+			continue
+		}
+		DeletePath(settings, key)
+		changes = append(changes, Change{Path: key, Action: ChangeRemove, Old: current})
+	}
+	return changes
+}
+
+// LookupPath returns the value at a dotted key path in nested maps
+func LookupPath(m map[string]any, path string) (any, bool) {
+	parts := strings.Split(path, ".")
+	for _, part := range parts[:len(parts)-1] {
+		next, ok := m[part].(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		m = next
+	}
+	v, ok := m[parts[len(parts)-1]]
+	return v, ok
+}
+
+// SetPath sets the value at a dotted key path, creating parent maps as needed
+func SetPath(m map[string]any, path string, value any) {
+	parts := strings.Split(path, ".")
+	for _, part := range parts[:len(parts)-1] {
+		next, ok := m[part].(map[string]any)
+		if !ok {
+			next = map[string]any{}
+			m[part] = next
+		}
+		m = next
+	}
+	m[parts[len(parts)-1]] = value
+}
+
+// DeletePath removes the value at a dotted key path and any parent maps left empty
+func DeletePath(m map[string]any, path string) {
+	parts := strings.Split(path, ".")
+	parents := []map[string]any{m}
+	for _, part := range parts[:len(parts)-1] {
+		next, ok := parents[len(parents)-1][part].(map[string]any)
+		if !ok {
+			return
+		}
+		parents = append(parents, next)
+	}
+	delete(parents[len(parents)-1], parts[len(parts)-1])
+	for i := len(parents) - 1; i > 0; i-- {
+		if len(parents[i]) > 0 {
+			break
+		}
+		delete(parents[i-1], parts[i-1])
+	}
+}
+
+// SameSettings reports whether two decoded config files hold the same
+// settings, ignoring number types and empty objects
+func SameSettings(a, b map[string]any) bool {
+	normalize := func(m map[string]any) string {
+		var v any
+		data, _ := json.Marshal(m)
+		_ = json.Unmarshal(data, &v)
+		data, _ = json.Marshal(pruneEmpty(v))
+		return string(data)
+	}
+	return normalize(a) == normalize(b)
+}
+
+// pruneEmpty drops empty maps from a decoded value
+func pruneEmpty(v any) any {
+	m, ok := v.(map[string]any)
+	if !ok {
+		return v
+	}
+	for k, child := range m {
+		child = pruneEmpty(child)
+		if cm, ok := child.(map[string]any); ok && len(cm) == 0 {
+			delete(m, k)
+			continue
+		}
+		m[k] = child
+	}
+	return m
+}
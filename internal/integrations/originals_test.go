@@ -0,0 +1,79 @@
+package integrations
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRevert(t *testing.T) {
+	managed := []string{"model", "env.TOKEN", "env.URL", "extra"}
+	originals := Originals{}
+	before := map[string]any{
+		"model": "opus",
+		"env":   map[string]any{"URL": "https://proxy.example.com", "KEEP": "1"},
+	}
+	RecordChanges(originals, before, managed, []string{"model", "env.TOKEN", "env.URL"})
+
+	// A later run must not overwrite the first recorded value
+	originals.Record("model", "costa/auto", true)
+
+	settings := map[string]any{
+		"model": "costa/auto",
+		"env":   map[string]any{"URL": "https://costa", "TOKEN": "secret", "KEEP": "1"},
+		"extra": "user-changed",
+	}
+	owned := func(key string, value any) bool { return key != "extra" }
+
+	changes := Revert(settings, managed, originals, owned)
+
+	wantChanges := []Change{
+		{Path: "model", Action: ChangeUpdate, Old: "costa/auto", New: "opus"},
+		{Path: "env.TOKEN", Action: ChangeRemove, Old: "secret"},
+		{Path: "env.URL", Action: ChangeUpdate, Old: "https://costa", New: "https://proxy.example.com"},
+	}
+	if !reflect.DeepEqual(changes, wantChanges) {
+		t.Errorf("changes = %v, want %v", changes, wantChanges)
+	}
+	want := map[string]any{
+		"model": "opus",
+		"env":   map[string]any{"URL": "https://proxy.example.com", "KEEP": "1"},
+		"extra": "user-changed",
+	}
+	if !reflect.DeepEqual(settings, want) {
+		t.Errorf("settings = %v, want %v", settings, want)
+	}
+}
+
+func TestDeletePath_PrunesEmptyParents(t *testing.T) {
+	m := map[string]any{"a": map[string]any{"b": map[string]any{"c": 1}}, "d": 2}
+	DeletePath(m, "a.b.c")
+	if !reflect.DeepEqual(m, map[string]any{"d": 2}) {
+		t.Errorf("got %v", m)
+	}
+}
+
+func TestOriginals_SaveLoadDelete(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	o := Originals{}
+	o.Record("model", "opus", true)
+	o.Record("env.TOKEN", nil, false)
+	if err := SaveOriginals("/tmp/settings.json", o); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadOriginals("/tmp/settings.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(loaded, o) {
+		t.Errorf("loaded %v, want %v", loaded, o)
+	}
+
+	if err := DeleteOriginals("/tmp/settings.json"); err != nil {
+		t.Fatal(err)
+	}
+	if loaded, _ := LoadOriginals("/tmp/settings.json"); len(loaded) != 0 {
+		t.Errorf("expected no originals after delete, got %v", loaded)
+	}
+}
@@ -0,0 +1,24 @@
+package integrations
+
+import (
+	"strings"
+)
+
+// Redact replaces every occurrence of each non-empty secret in text with
+// its redacted form
+func Redact(text string, secrets ...string) string {
+	for _, secret := range secrets {
+		if secret != "" {
+			text = strings.ReplaceAll(text, secret, RedactToken(secret))
+		}
+	}
+	return text
+}
+
+// RedactToken shortens a token for display, keeping its first six and last four characters
+func RedactToken(token string) string {
+	if len(token) <= 10 {
+		return "****"
+	}
+	return token[:6] + "****" + token[len(token)-4:]
+}
@@ -0,0 +1,121 @@
+package integrations
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/spf13/pflag"
+)
+
+// Prompt is an optional yes/no question asked by 'costa setup <app>' before
+// changes are applied. The default answer is yes.
+type Prompt struct {
+	// Skip reports whether flags already answered the question
+	Skip func(opts *ApplyOpts) bool
+	// Accept updates the options when the user answers yes
+	Accept func(opts *ApplyOpts)
+	// Question is printed before " [Y/n]: " and may span several lines
+	Question string
+}
+
+// Registration describes an integration to the setup commands
+type Registration struct {
+	// New creates the integration
+	New func() Integration
+	// Detect reports whether the tool is installed and its version; nil when
+	// the integration cannot detect its tool
+	Detect func() (version string, installed bool)
+	// Flags adds app-specific flags to 'costa setup <app>', bound to opts
+	Flags func(fs *pflag.FlagSet, opts *ApplyOpts)
+	// Secrets returns the tokens in the contents of one of the integration's
+	// config files, so diffs of the file can redact them
+	Secrets func(data []byte) []string
+	// Name is the setup subcommand, e.g. "claude-code"
+	Name string
+	// Title is the human-readable name, e.g. "Claude Code"
+	Title string
+	// ToolName names the detected tool in status output, e.g. "Claude CLI"
+	ToolName string
+	// InstallURL is shown when the tool is required but not installed
+	InstallURL string
+	Aliases    []string
+	// Scopes lists the supported scopes; the first is the default
+	Scopes  []Scope
+	Prompts []Prompt
+}
+
+// SupportsScope reports whether the integration can be configured at scope
+func (r Registration) SupportsScope(scope Scope) bool {
+	return slices.Contains(r.Scopes, scope)
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]Registration{}
+)
+
+// Register adds an integration to the registry. It is meant to be called from
+// an integration package's init and panics on duplicate names or aliases.
+func Register(r Registration) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if r.Name == "" || r.New == nil {
+		panic("integrations: Register requires a name and constructor")
+	}
+	if len(r.Scopes) == 0 {
+		r.Scopes = []Scope{ScopeUser}
+	}
+	for _, name := range append([]string{r.Name}, r.Aliases...) {
+		if _, ok := lookupLocked(name); ok {
+			panic(fmt.Sprintf("integrations: %q registered twice", name))
+		}
+	}
+	registry[r.Name] = r
+}
+
+// All returns the registered integrations sorted by name
+func All() []Registration {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	all := make([]Registration, 0, len(registry))
+	for _, r := range registry {
+		all = append(all, r)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Name < all[j].Name })
+	return all
+}
+
+// Lookup finds an integration by name or alias, ignoring case
+func Lookup(name string) (Registration, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	return lookupLocked(name)
+}
+
+func lookupLocked(name string) (Registration, bool) {
+	for _, r := range registry {
+		if strings.EqualFold(r.Name, name) {
+			return r, true
+		}
+		for _, alias := range r.Aliases {
+			if strings.EqualFold(alias, name) {
+				return r, true
+			}
+		}
+	}
+	return Registration{}, false
+}
+
+// Names returns the names of all registered integrations, sorted
+func Names() []string {
+	var names []string
+	for _, r := range All() {
+		names = append(names, r.Name)
+	}
+	return names
+}
@@ -0,0 +1,76 @@
+package integrations
+
+import (
+	"context"
+	"testing"
+)
+
+type fakeIntegration struct{}
+
+func (fakeIntegration) Name() string { return "fake" }
+
+func (fakeIntegration) Apply(context.Context, ApplyOpts) (ApplyResult, error) {
+	return ApplyResult{}, nil
+}
+
+func (fakeIntegration) Remove(context.Context, ApplyOpts) (ApplyResult, error) {
+	return ApplyResult{}, nil
+}
+
+func (fakeIntegration) Status(context.Context, Scope) (StatusResult, error) {
+	return StatusResult{}, nil
+}
+
+func withRegistry(t *testing.T) {
+	t.Helper()
+	saved := registry
+	registry = map[string]Registration{}
+	t.Cleanup(func() { registry = saved })
+}
+
+func TestRegister_LookupByAlias(t *testing.T) {
+	withRegistry(t)
+
+	Register(Registration{
+		Name:    "fake",
+		Aliases: []string{"fake tool"},
+		New:     func() Integration { return fakeIntegration{} },
+	})
+
+	reg, ok := Lookup("Fake Tool")
+	if !ok || reg.Name != "fake" {
+		t.Fatalf("expected alias lookup to find fake, got %+v (%v)", reg, ok)
+	}
+	if !reg.SupportsScope(ScopeUser) || reg.SupportsScope(ScopeProject) {
+		t.Errorf("expected registrations without scopes to default to user scope, got %v", reg.Scopes)
+	}
+	if _, ok := Lookup("other"); ok {
+		t.Errorf("expected unknown name not to be found")
+	}
+}
+
+func TestRegister_DuplicatePanics(t *testing.T) {
+	withRegistry(t)
+
+	Register(Registration{Name: "fake", New: func() Integration { return fakeIntegration{} }})
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected duplicate alias to panic")
+		}
+	}()
+	Register(Registration{Name: "other", Aliases: []string{"FAKE"}, New: func() Integration { return fakeIntegration{} }})
+}
+
+func TestAll_SortedByName(t *testing.T) {
+	withRegistry(t)
+
+	for _, name := range []string{"zed", "aider", "cursor"} {
+		Register(Registration{Name: name, New: func() Integration { return fakeIntegration{} }})
+	}
+
+	names := Names()
+	if len(names) != 3 || names[0] != "aider" || names[1] != "cursor" || names[2] != "zed" {
+		t.Errorf("expected sorted names, got %v", names)
+	}
+}
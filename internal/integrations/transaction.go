@@ -0,0 +1,160 @@
+package integrations
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/costa-app/costa-cli/internal/safefile"
+)
+
+// Transaction is how integrations write config files. Files are staged,
+// validated, backed up and then committed together with atomic renames; if
+// any step fails, files already replaced get their previous contents back.
+// Writes go through symlinks and keep each file's mode and owner.
+type Transaction struct {
+	result    *ApplyResult
+	app       string
+	backupDir string
+	scope     Scope
+	staged    []stagedFile
+}
+
+// stagedFile is a pending write and what is needed to undo it
+type stagedFile struct {
+	validate func([]byte) error
+	pending  *safefile.Pending
+	path     string
+	data     []byte
+	original []byte
+	existed  bool
+}
+
+// NewTransaction returns a transaction that backs up into opts.BackupDir, or
+// the app's default backup directory, and records backups and committed
+// files in result
+func NewTransaction(app string, opts ApplyOpts, result *ApplyResult) *Transaction {
+	return &Transaction{
+		result:    result,
+		app:       app,
+		backupDir: opts.BackupDir,
+		scope:     opts.Scope,
+	}
+}
+
+// Stage queues data to replace path on Commit. validate, if not nil, must
+// accept data for the commit to go ahead. Staging a path again replaces its data.
+func (tx *Transaction) Stage(path string, data []byte, validate func([]byte) error) {
+	for i := range tx.staged {
+		if tx.staged[i].path == path {
+			tx.staged[i].data, tx.staged[i].validate = data, validate
+			return
+		}
+	}
+	tx.staged = append(tx.staged, stagedFile{path: path, data: data, validate: validate})
+}
+
+// Commit validates, backs up and writes every staged file. On error no
+// staged file is left changed, and result.Committed stays empty.
+func (tx *Transaction) Commit() (err error) {
+	// Validate everything before touching the disk
+	for _, f := range tx.staged {
+		if f.validate == nil {
+			continue
+		}
+		if err := f.validate(f.data); err != nil {
+			return fmt.Errorf("invalid contents for %s: %w", f.path, err)
+		}
+	}
+
+	// Remember the current contents so they can be put back
+	for i := range tx.staged {
+		f := &tx.staged[i]
+		data, err := os.ReadFile(f.path)
+		switch {
+		case err == nil:
+			f.original, f.existed = data, true
+		case !os.IsNotExist(err):
+			return err
+		}
+	}
+
+	// Write every file next to its target first, so the renames cannot fail
+	// halfway for lack of space, and links that cannot be written through
+	// are refused before anything is backed up
+	defer func() {
+		for _, f := range tx.staged {
+			if f.pending != nil {
+				f.pending.Discard()
+			}
+		}
+	}()
+	for i := range tx.staged {
+		f := &tx.staged[i]
+		if f.pending, err = safefile.Prepare(f.path, f.data, 0600); err != nil {
+			return err
+		}
+	}
+
+	for _, f := range tx.staged {
+		backupPath, err := CreateBackup(tx.app, tx.scope, f.path, tx.backupDir)
+		if err != nil {
+			return &BackupError{Path: f.path, Err: err}
+		}
+		if backupPath != "" {
+			if tx.result.BackupPath == "" {
+				tx.result.BackupPath = backupPath
+			}
+			tx.result.BackupPaths = append(tx.result.BackupPaths, backupPath)
+		}
+	}
+
+	for i, f := range tx.staged {
+		if err := f.pending.Commit(); err != nil {
+			return tx.rollback(i, err)
+		}
+	}
+
+	for _, f := range tx.staged {
+		tx.result.Committed = append(tx.result.Committed, f.path)
+		if f.pending.Warning != "" {
+			tx.result.Warnings = append(tx.result.Warnings, f.pending.Warning)
+		}
+	}
+	return nil
+}
+
+// rollback restores the files committed before staged[failed] and returns
+// the commit error, with any files that could not be restored
+func (tx *Transaction) rollback(failed int, cause error) error {
+	var errs []error
+	for _, f := range tx.staged[:failed] {
+		var err error
+		if f.existed {
+			err = safefile.Write(f.pending.Path, f.original, 0600)
+		} else {
+			err = os.Remove(f.pending.Path)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to restore %s: %w", f.path, err))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to write %s: %w; rollback incomplete: %w", tx.staged[failed].path, cause, errors.Join(errs...))
+	}
+	return fmt.Errorf("failed to write %s, no changes were made: %w", tx.staged[failed].path, cause)
+}
+
+// BackupError reports a file that was not written because backing it up failed
+type BackupError struct {
+	Err  error
+	Path string
+}
+
+func (e *BackupError) Error() string {
+	return "failed to create backup of " + e.Path + ": " + e.Err.Error()
+}
+
+func (e *BackupError) Unwrap() error {
+	return e.Err
+}
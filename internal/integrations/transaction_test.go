@@ -0,0 +1,153 @@
+package integrations
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestTransaction_Commit(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	existing := filepath.Join(home, "config.toml")
+	if err := os.WriteFile(existing, []byte("a = 1\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	created := filepath.Join(home, "sub", "new.toml")
+
+	var result ApplyResult
+	tx := NewTransaction("test", ApplyOpts{Scope: ScopeUser}, &result)
+	tx.Stage(existing, []byte("a = 2\n"), nil)
+	tx.Stage(existing, []byte("a = 3\n"), nil)
+	tx.Stage(created, []byte("b = 1\n"), nil)
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result.Committed) != 2 || result.Committed[0] != existing || result.Committed[1] != created {
+		t.Errorf("Committed = %v", result.Committed)
+	}
+	// Only the existing file has something to back up
+	if len(result.BackupPaths) != 1 || result.BackupPath != result.BackupPaths[0] {
+		t.Fatalf("expected one backup, got %v", result.BackupPaths)
+	}
+	if data, _ := os.ReadFile(result.BackupPath); string(data) != "a = 1\n" {
+		t.Errorf("backup = %q, want the original contents", data)
+	}
+	if data, _ := os.ReadFile(existing); string(data) != "a = 3\n" {
+		t.Errorf("file = %q, want the last staged data", data)
+	}
+	if matches, _ := filepath.Glob(filepath.Join(home, ".*.tmp")); len(matches) > 0 {
+		t.Errorf("expected temp files to be cleaned up, got %v", matches)
+	}
+}
+
+func TestTransaction_ValidationFailureWritesNothing(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	first := filepath.Join(home, "first.json")
+	second := filepath.Join(home, "second.json")
+
+	var result ApplyResult
+	tx := NewTransaction("test", ApplyOpts{}, &result)
+	tx.Stage(first, []byte("{}"), nil)
+	tx.Stage(second, []byte("{"), func([]byte) error { return errors.New("truncated") })
+	if err := tx.Commit(); err == nil {
+		t.Fatal("expected validation error")
+	}
+
+	if _, err := os.Stat(first); !os.IsNotExist(err) {
+		t.Error("expected no file written when validation fails")
+	}
+	if len(result.Committed) != 0 {
+		t.Errorf("expected nothing committed, got %v", result.Committed)
+	}
+}
+
+func TestTransaction_RollsBackOnFailure(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	first := filepath.Join(home, "settings.json")
+	if err := os.WriteFile(first, []byte(`{"a": 1}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	created := filepath.Join(home, "created.json")
+
+	// A non-empty directory cannot be replaced by a rename
+	blocked := filepath.Join(home, "blocked.json")
+	if err := os.MkdirAll(filepath.Join(blocked, "child"), 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	var result ApplyResult
+	tx := NewTransaction("test", ApplyOpts{}, &result)
+	tx.Stage(first, []byte(`{"a": 2}`), nil)
+	tx.Stage(created, []byte(`{}`), nil)
+	tx.Stage(blocked, []byte(`{}`), nil)
+	if err := tx.Commit(); err == nil {
+		t.Fatal("expected commit to fail")
+	}
+
+	if data, _ := os.ReadFile(first); string(data) != `{"a": 1}` {
+		t.Errorf("expected %s rolled back, got %s", first, data)
+	}
+	if _, err := os.Stat(created); !os.IsNotExist(err) {
+		t.Errorf("expected %s removed on rollback", created)
+	}
+	if len(result.Committed) != 0 {
+		t.Errorf("expected nothing committed, got %v", result.Committed)
+	}
+	if matches, _ := filepath.Glob(filepath.Join(home, ".*.tmp")); len(matches) > 0 {
+		t.Errorf("expected temp files to be cleaned up, got %v", matches)
+	}
+}
+
+func TestTransaction_WritesThroughSymlinks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("symlinks need extra privileges on Windows")
+	}
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	// Managed by a dotfiles tool such as GNU stow
+	target := filepath.Join(home, "dotfiles", "settings.json")
+	if err := os.MkdirAll(filepath.Dir(target), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(target, []byte(`{"a": 1}`), 0640); err != nil {
+		t.Fatal(err)
+	}
+	link := filepath.Join(home, "settings.json")
+	if err := os.Symlink(target, link); err != nil {
+		t.Fatal(err)
+	}
+
+	var result ApplyResult
+	tx := NewTransaction("test", ApplyOpts{}, &result)
+	tx.Stage(link, []byte(`{"a": 2}`), nil)
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	if info, err := os.Lstat(link); err != nil || info.Mode()&os.ModeSymlink == 0 {
+		t.Errorf("expected %s to still be a symlink", link)
+	}
+	info, err := os.Stat(target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0640 {
+		t.Errorf("expected mode 0640 kept, got %v", info.Mode().Perm())
+	}
+	if data, _ := os.ReadFile(target); string(data) != `{"a": 2}` {
+		t.Errorf("target = %s", data)
+	}
+	if data, _ := os.ReadFile(result.BackupPath); string(data) != `{"a": 1}` {
+		t.Errorf("backup = %s, want the original contents", data)
+	}
+}
@@ -0,0 +1,489 @@
+// Package jsonedit edits keys in a JSON document in place. Only the bytes of
+// the edited keys change; key order, indentation, number formatting and the
+// trailing newline are kept. Comments and trailing commas (JSONC) are allowed.
+package jsonedit
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrUnsupported is returned for edits below a key whose value is not an object
+var ErrUnsupported = errors.New("key is inside a value that is not an object")
+
+// Document is a JSON document being edited
+type Document struct {
+	root   *object
+	data   []byte
+	indent string
+}
+
+// object is a JSON object. Offsets index into Document.data.
+type object struct {
+	members []member
+	start   int
+	end     int
+}
+
+// member is a key and its value within an object
+type member struct {
+	obj        *object
+	key        string
+	keyStart   int
+	valueStart int
+	valueEnd   int
+}
+
+// Parse indexes a JSON document whose top-level value is an object
+func Parse(data []byte) (*Document, error) {
+	d := &Document{data: append([]byte(nil), data...)}
+	if err := d.index(); err != nil {
+		return nil, err
+	}
+	d.indent = d.detectIndent()
+	return d, nil
+}
+
+// Bytes returns the edited document
+func (d *Document) Bytes() []byte {
+	return d.data
+}
+
+// Set sets the value at a dotted key path, creating parent objects as
+// needed. Existing values are replaced in place; new keys are added after
+// the last key of their object.
+func (d *Document) Set(path string, value any) error {
+	parts := strings.Split(path, ".")
+	obj := d.root
+	for i, part := range parts {
+		m := obj.member(part)
+		if m == nil {
+			for k := len(parts) - 1; k > i; k-- {
+				value = map[string]any{parts[k]: value}
+			}
+			return d.insert(obj, part, value)
+		}
+		if i == len(parts)-1 {
+			text, err := d.encode(value, d.lineIndent(m.keyStart))
+			if err != nil {
+				return err
+			}
+			d.splice(m.valueStart, m.valueEnd, text)
+			return d.index()
+		}
+		if m.obj == nil {
+			return ErrUnsupported
+		}
+		obj = m.obj
+	}
+	return nil
+}
+
+// Delete removes the key at a dotted key path, along with any objects left
+// empty by its removal. Deleting a missing key does nothing.
+func (d *Document) Delete(path string) error {
+	parts := strings.Split(path, ".")
+	obj := d.root
+	for _, part := range parts[:len(parts)-1] {
+		m := obj.member(part)
+		if m == nil || m.obj == nil {
+			return nil
+		}
+		obj = m.obj
+	}
+	idx := obj.index(parts[len(parts)-1])
+	if idx < 0 {
+		return nil
+	}
+
+	if len(obj.members) == 1 {
+		if len(parts) > 1 {
+			return d.Delete(strings.Join(parts[:len(parts)-1], "."))
+		}
+		d.splice(obj.start+1, obj.end-1, "")
+		return d.index()
+	}
+
+	m := obj.members[idx]
+	start := m.keyStart
+	ownLine := d.lineStart(start) == start-len(d.lineIndent(start))
+	if ownLine {
+		start = d.lineStart(start)
+	}
+
+	// The member's comma and a comment on the same line go with it
+	end := skipSpace(d.data, m.valueEnd)
+	hasComma := end < len(d.data) && d.data[end] == ','
+	if hasComma {
+		end = skipSpace(d.data, end+1)
+	}
+	if bytes.HasPrefix(d.data[end:], []byte("//")) {
+		for end < len(d.data) && d.data[end] != '\n' {
+			end++
+		}
+	}
+	if ownLine && end < len(d.data) && d.data[end] == '\n' {
+		end++
+	}
+
+	switch {
+	case hasComma:
+		d.splice(start, end, "")
+	case !ownLine:
+		// The last key on a shared line takes the previous comma with it
+		d.splice(obj.members[idx-1].valueEnd, m.valueEnd, "")
+	default:
+		// The last key on its own line leaves the previous key's comma dangling
+		d.splice(start, end, "")
+		if c := d.commaAfter(obj.members[idx-1].valueEnd); c >= 0 {
+			d.splice(c, c+1, "")
+		}
+	}
+	return d.index()
+}
+
+// insert adds a key to obj after its last member
+func (d *Document) insert(obj *object, key string, value any) error {
+	multiline := bytes.IndexByte(d.data[obj.start:obj.end], '\n') >= 0 ||
+		(len(obj.members) == 0 && bytes.IndexByte(d.data[d.root.start:d.root.end], '\n') >= 0)
+
+	indent := d.lineIndent(obj.start) + d.indent
+	if len(obj.members) > 0 && multiline {
+		indent = d.lineIndent(obj.members[len(obj.members)-1].keyStart)
+	}
+	name, _ := json.Marshal(key)
+	text, err := d.encode(value, indent)
+	if err != nil {
+		return err
+	}
+	text = string(name) + ": " + text
+
+	if len(obj.members) == 0 {
+		if multiline {
+			text = "\n" + indent + text + "\n" + d.lineIndent(obj.start)
+		}
+		d.splice(obj.start+1, obj.end-1, text)
+		return d.index()
+	}
+
+	last := obj.members[len(obj.members)-1]
+	pos := d.commaAfter(last.valueEnd)
+	if pos < 0 {
+		d.splice(last.valueEnd, last.valueEnd, ",")
+		pos = last.valueEnd
+	} else {
+		// Keep the trailing comma style
+		text += ","
+	}
+	pos++
+
+	if !multiline {
+		d.splice(pos, pos, " "+text)
+		return d.index()
+	}
+	// Go past a comment on the last key's line
+	if rest := skipSpace(d.data, pos); bytes.HasPrefix(d.data[rest:], []byte("//")) {
+		pos = rest
+		for pos < len(d.data) && d.data[pos] != '\n' {
+			pos++
+		}
+	}
+	d.splice(pos, pos, "\n"+indent+text)
+	return d.index()
+}
+
+// encode writes a value, indenting continuation lines of objects and arrays
+// below a key at indent. Compact documents get compact values.
+func (d *Document) encode(value any, indent string) (string, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if bytes.IndexByte(d.data, '\n') >= 0 {
+		enc.SetIndent(indent, d.indent)
+	}
+	if err := enc.Encode(value); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
+
+// commaAfter returns the offset of the comma following a value, or -1
+func (d *Document) commaAfter(pos int) int {
+	pos, err := skipSpaceAndComments(d.data, pos)
+	if err != nil || pos >= len(d.data) || d.data[pos] != ',' {
+		return -1
+	}
+	return pos
+}
+
+// detectIndent returns the indentation of the first key on its own line
+func (d *Document) detectIndent() string {
+	for _, m := range d.root.members {
+		if indent := d.lineIndent(m.keyStart); indent != "" && d.lineStart(m.keyStart)+len(indent) == m.keyStart {
+			return indent
+		}
+	}
+	return "  "
+}
+
+// lineStart returns the offset of the start of the line holding pos
+func (d *Document) lineStart(pos int) int {
+	return bytes.LastIndexByte(d.data[:pos], '\n') + 1
+}
+
+// lineIndent returns the leading whitespace of the line holding pos
+func (d *Document) lineIndent(pos int) string {
+	start := d.lineStart(pos)
+	end := skipSpace(d.data, start)
+	return string(d.data[start:end])
+}
+
+func (d *Document) splice(start, end int, text string) {
+	d.data = append(d.data[:start], append([]byte(text), d.data[end:]...)...)
+}
+
+// index records the position of every object and key
+func (d *Document) index() error {
+	p := &parser{data: d.data}
+	pos, err := p.skip(0)
+	if err != nil {
+		return err
+	}
+	if pos >= len(d.data) || d.data[pos] != '{' {
+		return errors.New("json: top-level value is not an object")
+	}
+	obj, end, err := p.object(pos)
+	if err != nil {
+		return err
+	}
+	if end, err = p.skip(end); err != nil {
+		return err
+	}
+	if end < len(d.data) {
+		return p.errorAt(end, "unexpected data after top-level value")
+	}
+	d.root = obj
+	return nil
+}
+
+func (o *object) index(key string) int {
+	for i, m := range o.members {
+		if m.key == key {
+			return i
+		}
+	}
+	return -1
+}
+
+func (o *object) member(key string) *member {
+	if i := o.index(key); i >= 0 {
+		return &o.members[i]
+	}
+	return nil
+}
+
+// parser scans JSONC values and records object members
+type parser struct {
+	data []byte
+}
+
+func (p *parser) skip(pos int) (int, error) {
+	pos, err := skipSpaceAndComments(p.data, pos)
+	if err != nil {
+		return pos, p.errorAt(pos, err.Error())
+	}
+	return pos, nil
+}
+
+// object parses the object starting at pos and returns the offset after it
+func (p *parser) object(pos int) (*object, int, error) {
+	obj := &object{start: pos}
+	pos++
+	for {
+		var err error
+		if pos, err = p.skip(pos); err != nil {
+			return nil, pos, err
+		}
+		if pos < len(p.data) && p.data[pos] == '}' {
+			obj.end = pos + 1
+			return obj, pos + 1, nil
+		}
+
+		if pos >= len(p.data) || p.data[pos] != '"' {
+			return nil, pos, p.errorAt(pos, "expected a key")
+		}
+		keyEnd, err := p.string(pos)
+		if err != nil {
+			return nil, pos, err
+		}
+		m := member{keyStart: pos}
+		if err := json.Unmarshal(p.data[pos:keyEnd], &m.key); err != nil {
+			return nil, pos, p.errorAt(pos, "invalid key")
+		}
+		if pos, err = p.skip(keyEnd); err != nil {
+			return nil, pos, err
+		}
+		if pos >= len(p.data) || p.data[pos] != ':' {
+			return nil, pos, p.errorAt(pos, "expected :")
+		}
+		if m.valueStart, err = p.skip(pos + 1); err != nil {
+			return nil, pos, err
+		}
+		m.obj, m.valueEnd, err = p.value(m.valueStart)
+		if err != nil {
+			return nil, pos, err
+		}
+		obj.members = append(obj.members, m)
+
+		if pos, err = p.skip(m.valueEnd); err != nil {
+			return nil, pos, err
+		}
+		switch {
+		case pos < len(p.data) && p.data[pos] == ',':
+			pos++
+		case pos < len(p.data) && p.data[pos] == '}':
+		default:
+			return nil, pos, p.errorAt(pos, "expected , or }")
+		}
+	}
+}
+
+// value parses the value starting at pos and returns the offset after it,
+// and the value itself if it is an object
+func (p *parser) value(pos int) (*object, int, error) {
+	if pos >= len(p.data) {
+		return nil, pos, p.errorAt(pos, "missing value")
+	}
+	switch p.data[pos] {
+	case '{':
+		return p.object(pos)
+	case '[':
+		pos++
+		for {
+			var err error
+			if pos, err = p.skip(pos); err != nil {
+				return nil, pos, err
+			}
+			if pos < len(p.data) && p.data[pos] == ']' {
+				return nil, pos + 1, nil
+			}
+			if _, pos, err = p.value(pos); err != nil {
+				return nil, pos, err
+			}
+			if pos, err = p.skip(pos); err != nil {
+				return nil, pos, err
+			}
+			switch {
+			case pos < len(p.data) && p.data[pos] == ',':
+				pos++
+			case pos < len(p.data) && p.data[pos] == ']':
+			default:
+				return nil, pos, p.errorAt(pos, "expected , or ]")
+			}
+		}
+	case '"':
+		end, err := p.string(pos)
+		return nil, end, err
+	default:
+		// Numbers, true, false and null
+		end := pos
+		for end < len(p.data) && strings.IndexByte("+-.0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ", p.data[end]) >= 0 {
+			end++
+		}
+		if end == pos || !json.Valid(p.data[pos:end]) {
+			return nil, pos, p.errorAt(pos, "invalid value")
+		}
+		return nil, end, nil
+	}
+}
+
+// string returns the offset just past the string starting at pos
+func (p *parser) string(pos int) (int, error) {
+	for i := pos + 1; i < len(p.data) && p.data[i] != '\n'; i++ {
+		switch p.data[i] {
+		case '\\':
+			i++
+		case '"':
+			return i + 1, nil
+		}
+	}
+	return pos, p.errorAt(pos, "unterminated string")
+}
+
+func (p *parser) errorAt(pos int, msg string) error {
+	line := bytes.Count(p.data[:min(pos, len(p.data))], []byte("\n")) + 1
+	return fmt.Errorf("json: line %d: %s", line, msg)
+}
+
+func skipSpace(data []byte, pos int) int {
+	for pos < len(data) && (data[pos] == ' ' || data[pos] == '\t') {
+		pos++
+	}
+	return pos
+}
+
+// skipSpaceAndComments returns the offset of the next character that is not
+// whitespace or part of a comment
+func skipSpaceAndComments(data []byte, pos int) (int, error) {
+	for pos < len(data) {
+		switch {
+		case data[pos] == ' ', data[pos] == '\t', data[pos] == '\n', data[pos] == '\r':
+			pos++
+		case bytes.HasPrefix(data[pos:], []byte("//")):
+			for pos < len(data) && data[pos] != '\n' {
+				pos++
+			}
+		case bytes.HasPrefix(data[pos:], []byte("/*")):
+			end := bytes.Index(data[pos+2:], []byte("*/"))
+			if end < 0 {
+				return pos, errors.New("unterminated comment")
+			}
+			pos += end + 4
+		default:
+			return pos, nil
+		}
+	}
+	return pos, nil
+}
+
+// Standardize returns data with comments and trailing commas blanked out, so
+// that it can be decoded with encoding/json. Line numbers are unchanged.
+func Standardize(data []byte) []byte {
+	out := append([]byte(nil), data...)
+	for pos := 0; pos < len(out); {
+		switch {
+		case out[pos] == '"':
+			end := pos + 1
+			for end < len(out) && out[end] != '"' && out[end] != '\n' {
+				if out[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			pos = end + 1
+		case bytes.HasPrefix(out[pos:], []byte("//")), bytes.HasPrefix(out[pos:], []byte("/*")):
+			end, err := skipSpaceAndComments(out, pos)
+			if err != nil {
+				// Left for the decoder to report
+				return out
+			}
+			for ; pos < end; pos++ {
+				if out[pos] != '\n' && out[pos] != '\r' {
+					out[pos] = ' '
+				}
+			}
+		case out[pos] == ',':
+			next, err := skipSpaceAndComments(out, pos+1)
+			if err == nil && next < len(out) && (out[next] == '}' || out[next] == ']') {
+				out[pos] = ' '
+			}
+			pos++
+		default:
+			pos++
+		}
+	}
+	return out
+}
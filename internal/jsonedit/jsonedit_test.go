@@ -0,0 +1,226 @@
+package jsonedit
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func edit(t *testing.T, input string, fn func(d *Document) error) string {
+	t.Helper()
+	d, err := Parse([]byte(input))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if err := fn(d); err != nil {
+		t.Fatalf("edit: %v", err)
+	}
+	return string(d.Bytes())
+}
+
+func TestSetReplacesValueInPlace(t *testing.T) {
+	input := `{
+    "theme": "dark",
+    "model": "opus",
+    "cleanupPeriodDays": 1e2,
+    "env": {
+        "FOO": "bar"
+    }
+}
+`
+	got := edit(t, input, func(d *Document) error { return d.Set("model", "costa/auto") })
+	want := `{
+    "theme": "dark",
+    "model": "costa/auto",
+    "cleanupPeriodDays": 1e2,
+    "env": {
+        "FOO": "bar"
+    }
+}
+`
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestSetAddsKeys(t *testing.T) {
+	input := `{
+	"theme": "dark",
+	"env": {
+		"FOO": "bar"
+	}
+}`
+	got := edit(t, input, func(d *Document) error {
+		if err := d.Set("env.ANTHROPIC_BASE_URL", "https://ai.costa.app/api"); err != nil {
+			return err
+		}
+		if err := d.Set("model", "costa/auto"); err != nil {
+			return err
+		}
+		return d.Set("statusLine", map[string]any{"type": "command", "padding": 0})
+	})
+	want := `{
+	"theme": "dark",
+	"env": {
+		"FOO": "bar",
+		"ANTHROPIC_BASE_URL": "https://ai.costa.app/api"
+	},
+	"model": "costa/auto",
+	"statusLine": {
+		"padding": 0,
+		"type": "command"
+	}
+}`
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestSetCreatesParentObjects(t *testing.T) {
+	got := edit(t, "{\n  \"theme\": \"dark\"\n}\n", func(d *Document) error {
+		return d.Set("env.ANTHROPIC_AUTH_TOKEN", "tok")
+	})
+	want := "{\n  \"theme\": \"dark\",\n  \"env\": {\n    \"ANTHROPIC_AUTH_TOKEN\": \"tok\"\n  }\n}\n"
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestSetEmptyObject(t *testing.T) {
+	if got := edit(t, "{}\n", func(d *Document) error { return d.Set("a", 1) }); got != "{\"a\": 1}\n" {
+		t.Errorf("got %q", got)
+	}
+	got := edit(t, "{\n  \"env\": {}\n}", func(d *Document) error { return d.Set("env.A", "b") })
+	if want := "{\n  \"env\": {\n    \"A\": \"b\"\n  }\n}"; got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestSetCompactDocument(t *testing.T) {
+	got := edit(t, `{"a":1,"b":{"c":true}}`, func(d *Document) error { return d.Set("b.d", []any{"x"}) })
+	if want := `{"a":1,"b":{"c":true, "d": ["x"]}}`; got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+func TestSetBelowScalarUnsupported(t *testing.T) {
+	d, err := Parse([]byte(`{"env": "nope"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Set("env.A", "b"); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("err = %v, want ErrUnsupported", err)
+	}
+}
+
+func TestJSONCComments(t *testing.T) {
+	input := `{
+  // editor theme
+  "theme": "dark", // keep this
+  /* model to use */
+  "model": "opus",
+  "env": {
+    "FOO": "bar", // trailing comma below
+  },
+}
+`
+	got := edit(t, input, func(d *Document) error {
+		if err := d.Set("model", "costa/auto"); err != nil {
+			return err
+		}
+		return d.Set("env.TOKEN", "x")
+	})
+	want := `{
+  // editor theme
+  "theme": "dark", // keep this
+  /* model to use */
+  "model": "costa/auto",
+  "env": {
+    "FOO": "bar", // trailing comma below
+    "TOKEN": "x",
+  },
+}
+`
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+
+	var v map[string]any
+	if err := json.Unmarshal(Standardize([]byte(got)), &v); err != nil {
+		t.Fatalf("Standardize output does not decode: %v", err)
+	}
+	if v["model"] != "costa/auto" {
+		t.Errorf("decoded %v", v)
+	}
+}
+
+func TestDelete(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		path  string
+		want  string
+	}{
+		{
+			name:  "first key",
+			input: "{\n  \"a\": 1,\n  \"b\": 2\n}\n",
+			path:  "a",
+			want:  "{\n  \"b\": 2\n}\n",
+		},
+		{
+			name:  "last key",
+			input: "{\n  \"a\": 1, // one\n  \"b\": 2 // two\n}\n",
+			path:  "b",
+			want:  "{\n  \"a\": 1 // one\n}\n",
+		},
+		{
+			name:  "middle key",
+			input: "{\n  \"a\": 1,\n  \"b\": {\n    \"x\": true\n  },\n  \"c\": 3\n}\n",
+			path:  "b",
+			want:  "{\n  \"a\": 1,\n  \"c\": 3\n}\n",
+		},
+		{
+			name:  "compact",
+			input: `{"a":1,"b":2}`,
+			path:  "b",
+			want:  `{"a":1}`,
+		},
+		{
+			name:  "prunes empty parent",
+			input: "{\n  \"theme\": \"dark\",\n  \"env\": {\n    \"TOKEN\": \"x\"\n  }\n}\n",
+			path:  "env.TOKEN",
+			want:  "{\n  \"theme\": \"dark\"\n}\n",
+		},
+		{
+			name:  "only key",
+			input: "{\n  \"a\": 1\n}\n",
+			path:  "a",
+			want:  "{}\n",
+		},
+		{
+			name:  "missing key",
+			input: `{"a":1}`,
+			path:  "env.B",
+			want:  `{"a":1}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := edit(t, tt.input, func(d *Document) error { return d.Delete(tt.path) })
+			if got != tt.want {
+				t.Errorf("got:\n%s\nwant:\n%s", got, tt.want)
+			}
+			if !json.Valid(Standardize([]byte(got))) {
+				t.Errorf("result is not valid JSON: %s", got)
+			}
+		})
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, input := range []string{"", "[]", `{"a": }`, `{"a": 1} x`, `{"a": "b`, "{/* x"} {
+		if _, err := Parse([]byte(input)); err == nil {
+			t.Errorf("Parse(%q) succeeded, want error", input)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+// Package safefile replaces files atomically without breaking how they are
+// managed: writes go through symlinks to the real file, keep its mode and
+// owner, and are synced to disk before and after the rename.
+package safefile
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// nixStore is where Nix and home-manager keep the read-only files they link to
+const nixStore = "/nix/store/"
+
+// maxLinks bounds how many symlinks are followed, to stop at link loops
+const maxLinks = 40
+
+// ReadOnlyError reports a path that links into the read-only Nix store
+type ReadOnlyError struct {
+	Path   string
+	Target string
+}
+
+func (e *ReadOnlyError) Error() string {
+	return fmt.Sprintf("%s is a link to %s in the read-only Nix store; change it in your Nix or home-manager configuration instead", e.Path, e.Target)
+}
+
+// Pending is a file written next to its target, waiting to replace it
+type Pending struct {
+	// Path is the file that Commit replaces, with symlinks resolved
+	Path string
+	// Warning explains an owner or group of the replaced file that could not be kept
+	Warning string
+	tmpPath string
+}
+
+// Write atomically replaces the file at path with data. New files get perm.
+func Write(path string, data []byte, perm fs.FileMode) error {
+	p, err := Prepare(path, data, perm)
+	if err != nil {
+		return err
+	}
+	defer p.Discard()
+	return p.Commit()
+}
+
+// Prepare writes data to a new temporary file next to the file path
+// resolves to, with that file's mode and owner, or perm if it does not
+// exist yet. Commit then moves it into place.
+func Prepare(path string, data []byte, perm fs.FileMode) (*Pending, error) {
+	target, err := Resolve(path)
+	if err != nil {
+		return nil, err
+	}
+
+	mode := perm
+	info, err := os.Stat(target)
+	switch {
+	case err == nil:
+		mode = info.Mode().Perm()
+	case !os.IsNotExist(err):
+		return nil, err
+	}
+
+	dir := filepath.Dir(target)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(target)+".*.tmp")
+	if err != nil {
+		return nil, err
+	}
+	p := &Pending{Path: target, tmpPath: tmp.Name()}
+
+	p.Warning, err = writeTemp(tmp, data, mode, info)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		p.Discard()
+		return nil, err
+	}
+	return p, nil
+}
+
+// writeTemp fills the temporary file and gives it the target's mode and,
+// as far as permissions allow, its owner
+func writeTemp(tmp *os.File, data []byte, mode fs.FileMode, target fs.FileInfo) (warning string, err error) {
+	if _, err := tmp.Write(data); err != nil {
+		return "", err
+	}
+	if err := tmp.Chmod(mode); err != nil {
+		return "", err
+	}
+	if target != nil {
+		if warning, err = copyOwner(tmp, target); err != nil {
+			return "", fmt.Errorf("cannot keep the owner of %s: %w", target.Name(), err)
+		}
+	}
+	return warning, tmp.Sync()
+}
+
+// Commit renames the temporary file over the target and syncs the directory
+func (p *Pending) Commit() error {
+	if p.tmpPath == "" {
+		return errors.New("safefile: already committed or discarded")
+	}
+	if err := os.Rename(p.tmpPath, p.Path); err != nil {
+		return err
+	}
+	p.tmpPath = ""
+	return syncDir(filepath.Dir(p.Path))
+}
+
+// Discard removes the temporary file if it has not been committed
+func (p *Pending) Discard() {
+	if p.tmpPath != "" {
+		_ = os.Remove(p.tmpPath)
+		p.tmpPath = ""
+	}
+}
+
+// Resolve follows symlinks at path to the file a write should replace, which
+// need not exist yet. Paths into the Nix store are refused with a ReadOnlyError.
+func Resolve(path string) (string, error) {
+	target := path
+	for range maxLinks {
+		info, err := os.Lstat(target)
+		if os.IsNotExist(err) || (err == nil && info.Mode()&fs.ModeSymlink == 0) {
+			return checkWritable(path, target)
+		}
+		if err != nil {
+			return "", err
+		}
+		link, err := os.Readlink(target)
+		if err != nil {
+			return "", err
+		}
+		if !filepath.IsAbs(link) {
+			link = filepath.Join(filepath.Dir(target), link)
+		}
+		target = link
+	}
+	return "", fmt.Errorf("%s: too many levels of symbolic links", path)
+}
+
+// checkWritable refuses targets in the Nix store, following links in the
+// target's parent directories too
+func checkWritable(path, target string) (string, error) {
+	resolved := target
+	if dir, err := filepath.EvalSymlinks(filepath.Dir(target)); err == nil {
+		resolved = filepath.Join(dir, filepath.Base(target))
+	}
+	for _, t := range []string{target, resolved} {
+		if strings.HasPrefix(filepath.ToSlash(t), nixStore) {
+			return "", &ReadOnlyError{Path: path, Target: t}
+		}
+	}
+	return target, nil
+}
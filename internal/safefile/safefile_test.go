@@ -0,0 +1,159 @@
+//go:build !windows
+
+package safefile
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+)
+
+func TestWrite_FollowsSymlinks(t *testing.T) {
+	dir := t.TempDir()
+	dotfiles := filepath.Join(dir, "dotfiles", "settings.json")
+	if err := os.MkdirAll(filepath.Dir(dotfiles), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(dotfiles, []byte("old"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	link := filepath.Join(dir, "settings.json")
+	if err := os.Symlink("dotfiles/settings.json", link); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Write(link, []byte("new"), 0600); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	info, err := os.Lstat(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode()&os.ModeSymlink == 0 {
+		t.Error("expected the symlink to be kept")
+	}
+	if data, _ := os.ReadFile(dotfiles); string(data) != "new" {
+		t.Errorf("expected the link target to be written, got %q", data)
+	}
+	if info, _ := os.Stat(dotfiles); info.Mode().Perm() != 0644 {
+		t.Errorf("expected mode 0644 kept, got %v", info.Mode().Perm())
+	}
+}
+
+func TestWrite_DanglingSymlinkCreatesTarget(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "repo", "config.toml")
+	link := filepath.Join(dir, "config.toml")
+	if err := os.Symlink(target, link); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Write(link, []byte("x = 1\n"), 0600); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if data, _ := os.ReadFile(target); string(data) != "x = 1\n" {
+		t.Errorf("expected the link target to be created, got %q", data)
+	}
+	if info, _ := os.Stat(target); info.Mode().Perm() != 0600 {
+		t.Errorf("expected new file mode 0600, got %v", info.Mode().Perm())
+	}
+}
+
+func TestWrite_IgnoresStaleTempFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "settings.json")
+	stale := path + ".tmp"
+	if err := os.WriteFile(stale, []byte("stale"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Write(path, []byte("new"), 0600); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "new" {
+		t.Errorf("got %q", data)
+	}
+	if data, _ := os.ReadFile(stale); string(data) != "stale" {
+		t.Error("expected the stale temp file to be left alone")
+	}
+	if matches, _ := filepath.Glob(filepath.Join(dir, ".*.tmp")); len(matches) > 0 {
+		t.Errorf("expected no temp files left, got %v", matches)
+	}
+}
+
+func TestWrite_RefusesNixStore(t *testing.T) {
+	dir := t.TempDir()
+	link := filepath.Join(dir, "settings.json")
+	if err := os.Symlink("/nix/store/abc123-home-manager-files/settings.json", link); err != nil {
+		t.Fatal(err)
+	}
+
+	err := Write(link, []byte("new"), 0600)
+	var readOnly *ReadOnlyError
+	if !errors.As(err, &readOnly) {
+		t.Fatalf("expected ReadOnlyError, got %v", err)
+	}
+	if readOnly.Path != link {
+		t.Errorf("expected error for %s, got %s", link, readOnly.Path)
+	}
+	if target, _ := os.Readlink(link); target != "/nix/store/abc123-home-manager-files/settings.json" {
+		t.Error("expected the link to be left alone")
+	}
+}
+
+func TestPrepare_DiscardLeavesTargetUnchanged(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "settings.json")
+	if err := os.WriteFile(path, []byte("old"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := Prepare(path, []byte("new"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.Discard()
+
+	if data, _ := os.ReadFile(path); string(data) != "old" {
+		t.Errorf("got %q", data)
+	}
+	if err := p.Commit(); err == nil {
+		t.Error("expected commit after discard to fail")
+	}
+}
+
+func TestWrite_KeepsOwnerAsRoot(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip("only root can give files away")
+	}
+	path := filepath.Join(t.TempDir(), "settings.json")
+	if err := os.WriteFile(path, []byte("old"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chown(path, 4242, 4343); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := Prepare(path, []byte("new"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p.Discard()
+	if err := p.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	if p.Warning != "" {
+		t.Errorf("expected no warning, got %q", p.Warning)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st := info.Sys().(*syscall.Stat_t); st.Uid != 4242 || st.Gid != 4343 {
+		t.Errorf("expected owner 4242:4343 kept, got %d:%d", st.Uid, st.Gid)
+	}
+}
@@ -0,0 +1,49 @@
+//go:build !windows
+
+package safefile
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"syscall"
+)
+
+// copyOwner gives f the owner and group of target, if they differ. Only root
+// can give a file away, so other users keep their own uid and, when they are
+// not in target's group, their own group too; the returned warning says so.
+func copyOwner(f *os.File, target fs.FileInfo) (string, error) {
+	st, ok := target.Sys().(*syscall.Stat_t)
+	if !ok {
+		return "", nil
+	}
+	uid, gid := int(st.Uid), int(st.Gid)
+	euid := os.Geteuid()
+	if uid == euid && gid == os.Getegid() {
+		return "", nil
+	}
+	if euid == 0 {
+		return "", f.Chown(uid, gid)
+	}
+
+	if uid != euid {
+		return fmt.Sprintf("%s was owned by uid %d and is now owned by uid %d", target.Name(), uid, euid), nil
+	}
+	if err := f.Chown(-1, gid); err != nil {
+		return fmt.Sprintf("%s was in group %d and is now in group %d: %v", target.Name(), gid, os.Getegid(), err), nil
+	}
+	return "", nil
+}
+
+// syncDir flushes a directory entry change, such as a rename, to disk
+func syncDir(dir string) error {
+	d, err := os.Open(dir) // #nosec G304 -- directory of a file being written
+	if err != nil {
+		return err
+	}
+	err = d.Sync()
+	if closeErr := d.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
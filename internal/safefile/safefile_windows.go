@@ -0,0 +1,18 @@
+//go:build windows
+
+package safefile
+
+import (
+	"io/fs"
+	"os"
+)
+
+// copyOwner does nothing on Windows, where files inherit their directory's ACL
+func copyOwner(f *os.File, target fs.FileInfo) (string, error) {
+	return "", nil
+}
+
+// syncDir does nothing on Windows, which cannot sync directories
+func syncDir(dir string) error {
+	return nil
+}
@@ -0,0 +1,675 @@
+// Package tomledit edits keys in a TOML document in place. Only the bytes of
+// the edited keys change; comments, key order and table layout elsewhere are
+// kept as they are.
+package tomledit
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ErrUnsupported is returned for edits inside inline tables or arrays of
+// tables, which cannot be made without rewriting the surrounding value
+var ErrUnsupported = errors.New("key is inside an inline table or array of tables")
+
+// Document is a TOML document being edited
+type Document struct {
+	data    []byte
+	entries []entry
+	tables  []table
+}
+
+// entry is a key/value line. Offsets index into Document.data.
+type entry struct {
+	path       []string
+	table      []string
+	indent     string
+	lineStart  int
+	valueStart int
+	valueEnd   int
+	lineEnd    int
+	inArray    bool
+}
+
+// table is a [table] or [[array]] header and the lines up to the next header
+type table struct {
+	path      []string
+	start     int
+	headerEnd int
+	end       int
+	array     bool
+}
+
+// Parse indexes a TOML document for editing
+func Parse(data []byte) (*Document, error) {
+	d := &Document{data: append([]byte(nil), data...)}
+	if err := d.index(); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// Bytes returns the edited document
+func (d *Document) Bytes() []byte {
+	return d.data
+}
+
+// Set sets the value at a dotted key path. Existing values are replaced in
+// place; new keys go next to their siblings, or into a new table at the end.
+// A map value replaces the whole table at path.
+func (d *Document) Set(path string, value any) error {
+	p := strings.Split(path, ".")
+	if d.blocked(p) {
+		return ErrUnsupported
+	}
+
+	if m, ok := value.(map[string]any); ok {
+		text, err := encodeTable(p, m)
+		if err != nil {
+			return err
+		}
+		// A table written as a single section is replaced where it stands
+		if k := d.findTable(p); k >= 0 && len(d.spans(p)) == 1 {
+			t := d.tables[k]
+			old := d.data[t.start:t.end]
+			trailing := old[len(bytes.TrimRight(old, " \t\r\n")):]
+			d.splice(t.start, t.end, strings.TrimRight(text, "\n")+string(trailing))
+			return d.index()
+		}
+		if err := d.Delete(path); err != nil {
+			return err
+		}
+		d.appendSection(text)
+		return d.index()
+	}
+
+	text, err := encodeValue(value)
+	if err != nil {
+		return err
+	}
+
+	if k := d.find(p); k >= 0 {
+		e := d.entries[k]
+		d.splice(e.valueStart, e.valueEnd, text)
+		return d.index()
+	}
+
+	parent := p[:len(p)-1]
+	switch {
+	case d.lastSibling(parent) >= 0:
+		e := d.entries[d.lastSibling(parent)]
+		d.insertLine(e.lineEnd, e.indent+formatKey(p[len(e.table):])+" = "+text+"\n")
+	case d.findTable(parent) >= 0:
+		t := d.tables[d.findTable(parent)]
+		d.insertLine(t.headerEnd, formatKey(p[len(parent):])+" = "+text+"\n")
+	case len(parent) == 0:
+		// A new top-level key goes before the first table and its comments
+		line := formatKey(p) + " = " + text + "\n"
+		if len(d.tables) == 0 {
+			d.insertLine(len(d.data), line)
+		} else {
+			d.insertLine(d.attachedStart(d.tables[0].start), line+"\n")
+		}
+	default:
+		d.appendSection("[" + formatKey(parent) + "]\n" + formatKey(p[len(parent):]) + " = " + text + "\n")
+	}
+	return d.index()
+}
+
+// Delete removes the key or table at a dotted key path, along with any
+// tables left empty by its removal. Deleting a missing key does nothing.
+func (d *Document) Delete(path string) error {
+	p := strings.Split(path, ".")
+	if d.blocked(p) {
+		return ErrUnsupported
+	}
+
+	spans := d.spans(p)
+	if len(spans) == 0 {
+		return nil
+	}
+
+	// Remove from the end so earlier offsets stay valid
+	atEOF := false
+	for k := len(spans) - 1; k >= 0; k-- {
+		atEOF = atEOF || spans[k].end == len(d.data)
+		d.splice(spans[k].start, spans[k].end, "")
+	}
+	if atEOF {
+		d.trimTrailingBlankLines()
+	}
+	if err := d.index(); err != nil {
+		return err
+	}
+	return d.pruneEmptyTables(p[:len(p)-1])
+}
+
+// span is a range of whole lines in the document
+type span struct{ start, end int }
+
+// spans returns the lines holding path and everything under it, in document
+// order. Entries inside a table that is itself removed are left out.
+func (d *Document) spans(path []string) []span {
+	var tables, spans []span
+	for _, t := range d.tables {
+		if hasPrefix(t.path, path) {
+			tables = append(tables, span{d.attachedStart(t.start), t.end})
+		}
+	}
+	spans = append(spans, tables...)
+	for _, e := range d.entries {
+		if e.inArray || !hasPrefix(e.path, path) {
+			continue
+		}
+		covered := false
+		for _, t := range tables {
+			covered = covered || (e.lineStart >= t.start && e.lineEnd <= t.end)
+		}
+		if !covered {
+			spans = append(spans, span{e.lineStart, e.lineEnd})
+		}
+	}
+	sort.Slice(spans, func(i, j int) bool { return spans[i].start < spans[j].start })
+	return spans
+}
+
+// pruneEmptyTables removes the table at path and its parents while they
+// have no keys, sub-tables or comments
+func (d *Document) pruneEmptyTables(path []string) error {
+	for ; len(path) > 0; path = path[:len(path)-1] {
+		k := d.findTable(path)
+		if k < 0 {
+			return nil
+		}
+		t := d.tables[k]
+		if len(bytes.TrimSpace(d.data[t.headerEnd:t.end])) > 0 {
+			return nil
+		}
+		for _, other := range d.tables {
+			if len(other.path) > len(path) && hasPrefix(other.path, path) {
+				return nil
+			}
+		}
+		for _, e := range d.entries {
+			if !e.inArray && hasPrefix(e.path, path) {
+				return nil
+			}
+		}
+		atEOF := t.end == len(d.data)
+		d.splice(t.start, t.end, "")
+		if atEOF {
+			d.trimTrailingBlankLines()
+		}
+		if err := d.index(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// blocked reports whether path lies inside a value or an array of tables
+func (d *Document) blocked(path []string) bool {
+	for _, e := range d.entries {
+		if !e.inArray && len(e.path) < len(path) && hasPrefix(path, e.path) {
+			return true
+		}
+	}
+	for _, t := range d.tables {
+		if t.array && hasPrefix(path, t.path) {
+			return true
+		}
+	}
+	return false
+}
+
+// find returns the index of the entry at path, or -1
+func (d *Document) find(path []string) int {
+	for k, e := range d.entries {
+		if !e.inArray && equal(e.path, path) {
+			return k
+		}
+	}
+	return -1
+}
+
+// lastSibling returns the index of the last entry whose parent is parent, or -1
+func (d *Document) lastSibling(parent []string) int {
+	last := -1
+	for k, e := range d.entries {
+		if !e.inArray && equal(e.path[:len(e.path)-1], parent) {
+			last = k
+		}
+	}
+	return last
+}
+
+// findTable returns the index of the [table] header at path, or -1
+func (d *Document) findTable(path []string) int {
+	for k, t := range d.tables {
+		if !t.array && equal(t.path, path) {
+			return k
+		}
+	}
+	return -1
+}
+
+// attachedStart moves a header's start back over the comment lines directly above it
+func (d *Document) attachedStart(start int) int {
+	for start > 0 {
+		prev := bytes.LastIndexByte(d.data[:start-1], '\n') + 1
+		if !bytes.HasPrefix(bytes.TrimLeft(d.data[prev:start], " \t"), []byte("#")) {
+			break
+		}
+		start = prev
+	}
+	return start
+}
+
+func (d *Document) splice(start, end int, text string) {
+	d.data = append(d.data[:start], append([]byte(text), d.data[end:]...)...)
+}
+
+// insertLine inserts a line at pos, starting a new line first if needed
+func (d *Document) insertLine(pos int, line string) {
+	if pos > 0 && d.data[pos-1] != '\n' {
+		line = "\n" + line
+	}
+	d.splice(pos, pos, line)
+}
+
+// appendSection adds text at the end, separated from the rest by a blank line
+func (d *Document) appendSection(text string) {
+	if len(d.data) > 0 {
+		if !bytes.HasSuffix(d.data, []byte("\n")) {
+			d.data = append(d.data, '\n')
+		}
+		if !bytes.HasSuffix(d.data, []byte("\n\n")) {
+			d.data = append(d.data, '\n')
+		}
+	}
+	d.data = append(d.data, text...)
+}
+
+// trimTrailingBlankLines leaves the document ending in at most one newline
+func (d *Document) trimTrailingBlankLines() {
+	trimmed := bytes.TrimRight(d.data, " \t\r\n")
+	if len(trimmed) == 0 {
+		d.data = d.data[:0]
+		return
+	}
+	d.data = append(trimmed, '\n')
+}
+
+// index records the position of every key/value line and table header
+func (d *Document) index() error {
+	d.entries, d.tables = nil, nil
+	s := d.data
+	var current []string
+	inArray := false
+
+	for i := 0; i < len(s); {
+		lineStart := i
+		i = skipSpace(s, i)
+		switch {
+		case i >= len(s):
+		case s[i] == '\n', s[i] == '\r', s[i] == '#':
+			end, err := endOfLine(s, i)
+			if err != nil {
+				return err
+			}
+			i = end
+		case s[i] == '[':
+			array := i+1 < len(s) && s[i+1] == '['
+			j := i + 1
+			closing := "]"
+			if array {
+				j++
+				closing = "]]"
+			}
+			path, j, err := parseKey(s, j)
+			if err != nil {
+				return err
+			}
+			j = skipSpace(s, j)
+			if !bytes.HasPrefix(s[j:], []byte(closing)) {
+				return d.errorAt(j, "expected "+closing)
+			}
+			end, err := endOfLine(s, j+len(closing))
+			if err != nil {
+				return err
+			}
+			d.tables = append(d.tables, table{path: path, start: lineStart, headerEnd: end, array: array})
+			current, inArray = path, array
+			i = end
+		default:
+			key, j, err := parseKey(s, i)
+			if err != nil {
+				return err
+			}
+			j = skipSpace(s, j)
+			if j >= len(s) || s[j] != '=' {
+				return d.errorAt(j, "expected =")
+			}
+			valueStart := skipSpace(s, j+1)
+			valueEnd, err := scanValue(s, valueStart)
+			if err != nil {
+				return err
+			}
+			end, err := endOfLine(s, valueEnd)
+			if err != nil {
+				return err
+			}
+			d.entries = append(d.entries, entry{
+				path:       append(append([]string{}, current...), key...),
+				table:      current,
+				indent:     string(s[lineStart:i]),
+				lineStart:  lineStart,
+				valueStart: valueStart,
+				valueEnd:   valueEnd,
+				lineEnd:    end,
+				inArray:    inArray,
+			})
+			i = end
+		}
+	}
+
+	// Each table runs up to the comments attached to the next header
+	for k := range d.tables {
+		d.tables[k].end = len(s)
+		if k+1 < len(d.tables) {
+			d.tables[k].end = max(d.attachedStart(d.tables[k+1].start), d.tables[k].headerEnd)
+		}
+	}
+	return nil
+}
+
+func (d *Document) errorAt(pos int, msg string) error {
+	line := bytes.Count(d.data[:min(pos, len(d.data))], []byte("\n")) + 1
+	return fmt.Errorf("toml: line %d: %s", line, msg)
+}
+
+// endOfLine skips trailing whitespace and a comment, and returns the offset after the newline
+func endOfLine(s []byte, i int) (int, error) {
+	i = skipSpace(s, i)
+	if i < len(s) && s[i] == '#' {
+		for i < len(s) && s[i] != '\n' {
+			i++
+		}
+	}
+	if i < len(s) && s[i] == '\r' {
+		i++
+	}
+	if i < len(s) {
+		if s[i] != '\n' {
+			return i, fmt.Errorf("toml: unexpected %q", s[i])
+		}
+		i++
+	}
+	return i, nil
+}
+
+func skipSpace(s []byte, i int) int {
+	for i < len(s) && (s[i] == ' ' || s[i] == '\t') {
+		i++
+	}
+	return i
+}
+
+// parseKey parses a dotted key of bare and quoted parts
+func parseKey(s []byte, i int) ([]string, int, error) {
+	var parts []string
+	for {
+		i = skipSpace(s, i)
+		var part string
+		switch {
+		case i < len(s) && (s[i] == '"' || s[i] == '\''):
+			end, err := scanString(s, i)
+			if err != nil {
+				return nil, i, err
+			}
+			raw := string(s[i:end])
+			if s[i] == '\'' {
+				part = raw[1 : len(raw)-1]
+			} else if part, err = strconv.Unquote(raw); err != nil {
+				return nil, i, fmt.Errorf("toml: invalid key %s", raw)
+			}
+			i = end
+		default:
+			j := i
+			for j < len(s) && isBareKeyChar(s[j]) {
+				j++
+			}
+			if j == i {
+				return nil, i, errors.New("toml: expected a key")
+			}
+			part, i = string(s[i:j]), j
+		}
+		parts = append(parts, part)
+
+		i = skipSpace(s, i)
+		if i >= len(s) || s[i] != '.' {
+			return parts, i, nil
+		}
+		i++
+	}
+}
+
+func isBareKeyChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '-'
+}
+
+// scanValue returns the offset just past the value starting at i
+func scanValue(s []byte, i int) (int, error) {
+	if i >= len(s) {
+		return i, errors.New("toml: missing value")
+	}
+	switch s[i] {
+	case '"', '\'':
+		return scanString(s, i)
+	case '[', '{':
+		depth := 0
+		for j := i; j < len(s); {
+			switch s[j] {
+			case '"', '\'':
+				end, err := scanString(s, j)
+				if err != nil {
+					return j, err
+				}
+				j = end
+			case '#':
+				for j < len(s) && s[j] != '\n' {
+					j++
+				}
+			case '[', '{':
+				depth++
+				j++
+			case ']', '}':
+				depth--
+				j++
+				if depth == 0 {
+					return j, nil
+				}
+			default:
+				j++
+			}
+		}
+		return i, errors.New("toml: unterminated array or inline table")
+	default:
+		// Numbers, booleans and dates, which may contain a space
+		j := i
+		for j < len(s) && s[j] != '#' && s[j] != '\n' && s[j] != '\r' {
+			j++
+		}
+		for j > i && (s[j-1] == ' ' || s[j-1] == '\t') {
+			j--
+		}
+		if j == i {
+			return i, errors.New("toml: missing value")
+		}
+		return j, nil
+	}
+}
+
+// scanString returns the offset just past the string starting at i
+func scanString(s []byte, i int) (int, error) {
+	quote := s[i]
+	if bytes.HasPrefix(s[i:], []byte{quote, quote, quote}) {
+		for j := i + 3; j < len(s); j++ {
+			if quote == '"' && s[j] == '\\' {
+				j++
+				continue
+			}
+			if bytes.HasPrefix(s[j:], []byte{quote, quote, quote}) {
+				// Up to two quotes may end the content right before the delimiter
+				end := j + 3
+				for k := 0; k < 2 && end < len(s) && s[end] == quote; k++ {
+					end++
+				}
+				return end, nil
+			}
+		}
+		return i, errors.New("toml: unterminated multi-line string")
+	}
+	for j := i + 1; j < len(s) && s[j] != '\n'; j++ {
+		if quote == '"' && s[j] == '\\' {
+			j++
+			continue
+		}
+		if s[j] == quote {
+			return j + 1, nil
+		}
+	}
+	return i, errors.New("toml: unterminated string")
+}
+
+// formatKey writes a dotted key, quoting parts that are not bare keys
+func formatKey(path []string) string {
+	parts := make([]string, len(path))
+	for k, part := range path {
+		parts[k] = part
+		bare := part != ""
+		for i := 0; i < len(part); i++ {
+			bare = bare && isBareKeyChar(part[i])
+		}
+		if !bare {
+			parts[k] = quote(part)
+		}
+	}
+	return strings.Join(parts, ".")
+}
+
+// quote writes a TOML basic string
+func quote(s string) string {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(s)
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// encodeValue writes a value as it appears after "key = "
+func encodeValue(v any) (string, error) {
+	switch v := v.(type) {
+	case string:
+		return quote(v), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case int:
+		return strconv.Itoa(v), nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case float64:
+		switch {
+		case math.IsNaN(v):
+			return "nan", nil
+		case math.IsInf(v, 1):
+			return "inf", nil
+		case math.IsInf(v, -1):
+			return "-inf", nil
+		case v == math.Trunc(v) && math.Abs(v) < 1e15:
+			// Whole numbers, e.g. read back from JSON, are written as integers
+			return strconv.FormatInt(int64(v), 10), nil
+		}
+		return strconv.FormatFloat(v, 'g', -1, 64), nil
+	case []any:
+		items := make([]string, len(v))
+		for k, item := range v {
+			text, err := encodeValue(item)
+			if err != nil {
+				return "", err
+			}
+			items[k] = text
+		}
+		return "[" + strings.Join(items, ", ") + "]", nil
+	case map[string]any:
+		keys := sortedKeys(v)
+		items := make([]string, len(keys))
+		for k, key := range keys {
+			text, err := encodeValue(v[key])
+			if err != nil {
+				return "", err
+			}
+			items[k] = formatKey([]string{key}) + " = " + text
+		}
+		return "{ " + strings.Join(items, ", ") + " }", nil
+	default:
+		return "", fmt.Errorf("toml: cannot encode %T", v)
+	}
+}
+
+// encodeTable writes m as a [path] table followed by its sub-tables
+func encodeTable(path []string, m map[string]any) (string, error) {
+	var sb strings.Builder
+	sb.WriteString("[" + formatKey(path) + "]\n")
+	var subtables []string
+	for _, key := range sortedKeys(m) {
+		if _, ok := m[key].(map[string]any); ok {
+			subtables = append(subtables, key)
+			continue
+		}
+		text, err := encodeValue(m[key])
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString(formatKey([]string{key}) + " = " + text + "\n")
+	}
+	for _, key := range subtables {
+		text, err := encodeTable(append(append([]string{}, path...), key), m[key].(map[string]any))
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString("\n" + text)
+	}
+	return sb.String(), nil
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func hasPrefix(path, prefix []string) bool {
+	return len(path) >= len(prefix) && equal(path[:len(prefix)], prefix)
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,231 @@
+package tomledit
+
+import (
+	"errors"
+	"testing"
+)
+
+func edit(t *testing.T, input string, fn func(d *Document) error) string {
+	t.Helper()
+	d, err := Parse([]byte(input))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if err := fn(d); err != nil {
+		t.Fatalf("edit: %v", err)
+	}
+	return string(d.Bytes())
+}
+
+func TestSetReplacesValueInPlace(t *testing.T) {
+	input := `# my codex config
+model = "gpt-5"   # the default
+approval_policy = "never"
+
+[features]
+web_search_request = false # off for now
+`
+	got := edit(t, input, func(d *Document) error {
+		if err := d.Set("model", "costa/auto"); err != nil {
+			return err
+		}
+		return d.Set("features.web_search_request", true)
+	})
+
+	want := `# my codex config
+model = "costa/auto"   # the default
+approval_policy = "never"
+
+[features]
+web_search_request = true # off for now
+`
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestSetAddsKeys(t *testing.T) {
+	input := `# top comment
+approval_policy = "never"
+
+# features I use
+[features]
+streamable_shell = true
+
+[mcp_servers.docs]
+command = "docs-mcp"
+args = ["--port", "8080"]
+`
+	got := edit(t, input, func(d *Document) error {
+		for _, kv := range []struct {
+			value any
+			path  string
+		}{
+			{path: "model_provider", value: "costa"},
+			{path: "features.web_search_request", value: true},
+			{path: "model_providers.costa.name", value: "costa"},
+			{path: "model_providers.costa.base_url", value: "https://ai.costa.app/api/v1"},
+		} {
+			if err := d.Set(kv.path, kv.value); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	want := `# top comment
+approval_policy = "never"
+model_provider = "costa"
+
+# features I use
+[features]
+streamable_shell = true
+web_search_request = true
+
+[mcp_servers.docs]
+command = "docs-mcp"
+args = ["--port", "8080"]
+
+[model_providers.costa]
+name = "costa"
+base_url = "https://ai.costa.app/api/v1"
+`
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestSetAddsKeyToEmptyTable(t *testing.T) {
+	got := edit(t, "[features]\n\n[other]\nx = 1\n", func(d *Document) error {
+		return d.Set("features.web_search_request", true)
+	})
+	want := "[features]\nweb_search_request = true\n\n[other]\nx = 1\n"
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestSetTableReplacesSection(t *testing.T) {
+	input := `[model_providers.costa]
+name = "costa"
+env_key = "COSTA_KEY"
+
+[profiles.fast]
+model = "o4-mini"
+`
+	got := edit(t, input, func(d *Document) error {
+		return d.Set("model_providers.costa", map[string]any{"name": "Costa", "base_url": "http://localhost"})
+	})
+	want := `[model_providers.costa]
+base_url = "http://localhost"
+name = "Costa"
+
+[profiles.fast]
+model = "o4-mini"
+`
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestDeleteTablePrunesEmptyParent(t *testing.T) {
+	input := `model = "o3"
+
+[model_providers]
+
+# Costa provider
+[model_providers.costa]
+name = "costa"
+experimental_bearer_token = "secret"
+
+# local models
+[profiles.local]
+model = "llama"
+`
+	got := edit(t, input, func(d *Document) error {
+		return d.Delete("model_providers.costa")
+	})
+	want := `model = "o3"
+
+# local models
+[profiles.local]
+model = "llama"
+`
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestDeleteKeepsOtherProviders(t *testing.T) {
+	input := `[model_providers.costa]
+name = "costa"
+
+[model_providers.ollama]
+name = "Ollama"
+`
+	got := edit(t, input, func(d *Document) error {
+		return d.Delete("model_providers.costa")
+	})
+	want := `[model_providers.ollama]
+name = "Ollama"
+`
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestDeleteLastKeyAtEOF(t *testing.T) {
+	got := edit(t, "model = \"o3\"\n\n[features]\nweb_search_request = true\n", func(d *Document) error {
+		return d.Delete("features.web_search_request")
+	})
+	if want := "model = \"o3\"\n"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestDeleteMissingKey(t *testing.T) {
+	input := "model = \"o3\"\n"
+	got := edit(t, input, func(d *Document) error { return d.Delete("model_provider") })
+	if got != input {
+		t.Errorf("got %q, want unchanged", got)
+	}
+}
+
+func TestStringsAndArraysAreSkipped(t *testing.T) {
+	input := `notes = """
+[not_a_table]
+model = "x"
+"""
+paths = [
+  "a", # first
+  "b]",
+]
+literal = 'C:\path'
+`
+	got := edit(t, input, func(d *Document) error { return d.Set("model", "costa/auto") })
+	want := input + "model = \"costa/auto\"\n"
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestInlineTableUnsupported(t *testing.T) {
+	d, err := Parse([]byte(`model_providers = { costa = { name = "costa" } }` + "\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Set("model_providers.costa.name", "x"); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("Set err = %v, want ErrUnsupported", err)
+	}
+	if err := d.Delete("model_providers.costa"); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("Delete err = %v, want ErrUnsupported", err)
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, input := range []string{"model = \"unterminated\n", "[table\n", "key value\n"} {
+		if _, err := Parse([]byte(input)); err == nil {
+			t.Errorf("Parse(%q) succeeded, want error", input)
+		}
+	}
+}